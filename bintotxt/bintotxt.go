@@ -1,68 +1,64 @@
 package main
 
 import (
-	"encoding/binary" // Для чтения бинарных данных из файла.
-	"flag"           // Для парсинга аргументов командной строки.
-	"fmt"            // Для форматированного ввода/вывода.
-	"io"             // Для работы с вводом/выводом.
-	"os"             // Для работы с файловой системой.
+	"flag" // Для парсинга аргументов командной строки.
+	"fmt"  // Для форматированного ввода/вывода.
+	"os"   // Для работы с файловой системой.
+
+	"github.com/MTXIK/Boruvkas/pkg/graphio" // Контейнерный формат файлов графа.
+)
+
+const (
+	minInt16 = -1 << 15
+	maxInt16 = 1<<15 - 1
 )
 
 // Edge представляет ребро в графе.
 type Edge struct {
-	from   int16  // Начальная вершина ребра.
-	to     int16  // Конечная вершина ребра.
-	weight int16  // Вес ребра.
+	from   int16 // Начальная вершина ребра.
+	to     int16 // Конечная вершина ребра.
+	weight int16 // Вес ребра.
 }
 
-// readGraph считывает граф из бинарного файла.
-// Формат файла: сначала количество вершин (int16),
-// затем тройки (from, to, weight) в формате int16 каждая.
+// readGraph считывает граф из файла через graphio.Reader, который сам
+// определяет версию формата: новый контейнер (магия "BRVK" + footer с CRC32)
+// или легаси int16-формат (просто count + триплеты), на котором работал этот
+// инструмент раньше.
 func readGraph(filename string) (int16, []Edge, error) {
-	// Открываем бинарный файл для чтения.
+	// Открываем файл для чтения.
 	file, err := os.Open(filename)
 	if err != nil {
 		return 0, nil, fmt.Errorf("не удалось открыть файл %s: %v", filename, err)
 	}
 	defer file.Close() // Закрываем файл при выходе из функции.
 
-	var numVertices int16
-	// Читаем количество вершин.
-	err = binary.Read(file, binary.LittleEndian, &numVertices)
+	r, err := graphio.NewReader(file)
 	if err != nil {
-		return 0, nil, fmt.Errorf("не удалось прочитать количество вершин: %v", err)
+		return 0, nil, fmt.Errorf("не удалось разобрать заголовок графа: %v", err)
 	}
 
-	edges := []Edge{} // Срез для хранения ребер.
-
-	for {
-		var from, to, weight int16
-		// Читаем тройку (from, to, weight).
-		err = binary.Read(file, binary.LittleEndian, &from)
-		if err == io.EOF {
-			break // Достигнут конец файла.
-		}
-		if err != nil {
-			return 0, nil, fmt.Errorf("ошибка при чтении вершины 'from': %v", err)
-		}
+	if r.NumVertices() > 1<<15-1 {
+		return 0, nil, fmt.Errorf("граф содержит %d вершин, это больше, чем умеет bintotxt (макс. %d)", r.NumVertices(), 1<<15-1)
+	}
+	numVertices := int16(r.NumVertices())
 
-		err = binary.Read(file, binary.LittleEndian, &to)
-		if err != nil {
-			return 0, nil, fmt.Errorf("ошибка при чтении вершины 'to': %v", err)
-		}
+	gioEdges, err := r.ReadAll()
+	if err != nil {
+		return 0, nil, fmt.Errorf("не удалось прочитать рёбра: %v", err)
+	}
 
-		err = binary.Read(file, binary.LittleEndian, &weight)
-		if err != nil {
-			return 0, nil, fmt.Errorf("ошибка при чтении веса ребра: %v", err)
+	edges := make([]Edge, 0, len(gioEdges))
+	for _, e := range gioEdges {
+		// Проверка корректности индексов вершин и веса: bintotxt оперирует
+		// только небольшими графами, укладывающимися в int16 (графы покрупнее
+		// нужно обрабатывать напрямую через graphio).
+		if e.From < 0 || e.From >= int32(numVertices) || e.To < 0 || e.To >= int32(numVertices) {
+			return 0, nil, fmt.Errorf("недопустимые индексы вершин: from=%d, to=%d", e.From, e.To)
 		}
-
-		// Проверка корректности индексов вершин.
-		if from < 0 || from >= numVertices || to < 0 || to >= numVertices {
-			return 0, nil, fmt.Errorf("недопустимые индексы вершин: from=%d, to=%d", from, to)
+		if e.Weight < minInt16 || e.Weight > maxInt16 {
+			return 0, nil, fmt.Errorf("вес ребра %d выходит за пределы int16", e.Weight)
 		}
-
-		// Добавляем ребро в срез.
-		edges = append(edges, Edge{from, to, weight})
+		edges = append(edges, Edge{from: int16(e.From), to: int16(e.To), weight: int16(e.Weight)})
 	}
 
 	return numVertices, edges, nil // Возвращаем количество вершин и список ребер.
@@ -97,36 +93,68 @@ func writeEdges(filename string, edges []Edge) error {
 	return nil // Возвращаем nil, если всё прошло успешно.
 }
 
-// main является точкой входа в программу.
-// Он парсит аргументы командной строки, считывает граф из бинарного файла,
-// и записывает все ребра в текстовый файл.
-func main() {
-	// Определяем флаги командной строки.
-	inputFile := flag.String("i", "", "Имя входного бинарного файла")
-	outputFile := flag.String("o", "output.txt", "Имя выходного текстового файла")
-	flag.Parse() // Парсим флаги.
+// cmdDump — старое поведение инструмента (раньше это было единственное, что
+// он умел): читает граф (контейнер graphio или легаси int16) и сохраняет его
+// человекочитаемым текстом.
+func cmdDump(args []string) {
+	fs := flag.NewFlagSet("dump", flag.ExitOnError)
+	inputFile := fs.String("i", "", "Имя входного файла графа")
+	outputFile := fs.String("o", "output.txt", "Имя выходного текстового файла")
+	fs.Parse(args)
 
-	// Проверяем, что имя входного файла было предоставлено.
 	if *inputFile == "" {
-		fmt.Println("Использование: go run read_graph.go -i inputfile [-o outputfile]")
-		return
+		fmt.Println("Использование: bintotxt dump -i inputfile [-o outputfile]")
+		os.Exit(1)
 	}
 
-	// Считываем граф из бинарного файла.
 	numVertices, edges, err := readGraph(*inputFile)
 	if err != nil {
 		fmt.Printf("Ошибка при чтении графа: %v\n", err)
-		return
+		os.Exit(1)
 	}
-
 	fmt.Printf("Граф успешно считан: %d вершин, %d ребер.\n", numVertices, len(edges))
 
-	// Записываем ребра в текстовый файл.
-	err = writeEdges(*outputFile, edges)
-	if err != nil {
+	if err := writeEdges(*outputFile, edges); err != nil {
 		fmt.Printf("Ошибка при записи ребер: %v\n", err)
-		return
+		os.Exit(1)
 	}
-
 	fmt.Printf("Ребра успешно записаны в файл %s.\n", *outputFile)
 }
+
+// main — точка входа. bintotxt теперь умеет не только дамп в текст, но и
+// конвертацию между форматами (convert), сбор статистики (stat) и построение
+// MST (mst); dump повторяет старое поведение, но вызывается теперь как
+// подкоманда: запуск без подкоманды (bintotxt -i ...) печатает справку.
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(1)
+	}
+
+	switch os.Args[1] {
+	case "dump":
+		cmdDump(os.Args[2:])
+	case "convert":
+		cmdConvert(os.Args[2:])
+	case "stat":
+		cmdStat(os.Args[2:])
+	case "mst":
+		cmdMST(os.Args[2:])
+	case "verify":
+		cmdVerify(os.Args[2:])
+	default:
+		usage()
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Println("Использование: bintotxt <dump|convert|stat|mst|verify> [флаги]")
+	fmt.Println("  dump    -i file [-o output.txt]                         — старый текстовый дамп")
+	fmt.Println("  convert -i file -o file [-informat F] [-outformat F] [-v3] — конвертация между форматами")
+	fmt.Println("  stat    -i file [-informat F]                           — статистика по графу")
+	fmt.Println("  mst     -i file [-informat F] [-o file] [-outformat F] [-workers N] [-v3] — минимальное остовное дерево")
+	fmt.Println("  verify  -i file [-recover]                              — проверка CRC32 бинарного контейнера")
+	fmt.Println("Форматы (F): binary, text, dimacs, ndjson. Без -informat/-outformat формат определяется по расширению файла.")
+	fmt.Println("-v3 (для convert/mst с бинарным выходом): писать Version3 (блочный CRC-framing для восстановления) вместо Version2+gzip.")
+}
@@ -0,0 +1,391 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/MTXIK/Boruvkas/pkg/boruvka"
+	"github.com/MTXIK/Boruvkas/pkg/graphio"
+)
+
+// resolveFormat определяет формат графа: если explicit не пуст (флаг
+// -informat/-outformat был передан), разбирает его через graphio.ParseFormat,
+// иначе определяет формат по расширению path.
+func resolveFormat(explicit, path string) (graphio.Format, error) {
+	if explicit != "" {
+		return graphio.ParseFormat(explicit)
+	}
+	return graphio.DetectFormat(path)
+}
+
+// cmdConvert читает граф в одном формате и записывает его в другом.
+// Рёбра вычитываются в память целиком: для бинарного и DIMACS форматов
+// количество рёбер должно быть известно заранее, чтобы попасть в заголовок.
+func cmdConvert(args []string) {
+	fs := flag.NewFlagSet("convert", flag.ExitOnError)
+	inputFile := fs.String("i", "", "Имя входного файла графа")
+	outputFile := fs.String("o", "", "Имя выходного файла графа")
+	inFormat := fs.String("informat", "", "Формат входного файла (binary, text, dimacs, ndjson); по умолчанию — по расширению -i")
+	outFormat := fs.String("outformat", "", "Формат выходного файла; по умолчанию — по расширению -o")
+	v3 := fs.Bool("v3", false, "Писать бинарный выход в Version3 (блочный CRC-framing для восстановления, без сжатия) вместо Version2+gzip по умолчанию")
+	fs.Parse(args)
+
+	if *inputFile == "" || *outputFile == "" {
+		fmt.Println("Использование: bintotxt convert -i inputfile -o outputfile [-informat F] [-outformat F] [-v3]")
+		os.Exit(1)
+	}
+
+	inFmt, err := resolveFormat(*inFormat, *inputFile)
+	if err != nil {
+		fmt.Printf("Ошибка: %v\n", err)
+		os.Exit(1)
+	}
+	outFmt, err := resolveFormat(*outFormat, *outputFile)
+	if err != nil {
+		fmt.Printf("Ошибка: %v\n", err)
+		os.Exit(1)
+	}
+
+	r, err := graphio.OpenReader(inFmt, *inputFile)
+	if err != nil {
+		fmt.Printf("Ошибка при открытии графа: %v\n", err)
+		os.Exit(1)
+	}
+	edges, err := graphio.ReadAll(r)
+	r.Close()
+	if err != nil {
+		fmt.Printf("Ошибка при чтении графа: %v\n", err)
+		os.Exit(1)
+	}
+	numVertices := r.NumVertices()
+
+	w, err := graphio.CreateWriter(outFmt, *outputFile, outputOptions(*v3), numVertices, uint64(len(edges)))
+	if err != nil {
+		fmt.Printf("Ошибка при создании выходного файла: %v\n", err)
+		os.Exit(1)
+	}
+	for _, e := range edges {
+		if err := w.WriteEdge(e); err != nil {
+			fmt.Printf("Ошибка при записи ребра: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	if err := w.Close(); err != nil {
+		fmt.Printf("Ошибка при закрытии выходного файла: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("Граф сконвертирован: %d вершин, %d ребер, %s -> %s.\n", numVertices, len(edges), inFmt, outFmt)
+}
+
+// outputOptions возвращает Options для записи бинарного выхода: по умолчанию
+// Version2+gzip (graphio.DefaultOptions), либо, если запрошен -v3, Version3
+// без сжатия — оно обязано быть выключено явно, так как блочный CRC-framing
+// несовместим со сжатым потоком (см. ErrBlockFramingRequiresUncompressed).
+func outputOptions(v3 bool) graphio.Options {
+	if v3 {
+		return graphio.Options{Version: graphio.Version3}
+	}
+	return graphio.DefaultOptions()
+}
+
+// cmdStat вычисляет сводную статистику по графу одним потоковым проходом
+// (числа вершин и рёбер, суммарный и экстремальные веса), не держа рёбра в
+// памяти.
+func cmdStat(args []string) {
+	fs := flag.NewFlagSet("stat", flag.ExitOnError)
+	inputFile := fs.String("i", "", "Имя входного файла графа")
+	inFormat := fs.String("informat", "", "Формат входного файла (binary, text, dimacs, ndjson); по умолчанию — по расширению -i")
+	fs.Parse(args)
+
+	if *inputFile == "" {
+		fmt.Println("Использование: bintotxt stat -i inputfile [-informat F]")
+		os.Exit(1)
+	}
+
+	inFmt, err := resolveFormat(*inFormat, *inputFile)
+	if err != nil {
+		fmt.Printf("Ошибка: %v\n", err)
+		os.Exit(1)
+	}
+
+	r, err := graphio.OpenReader(inFmt, *inputFile)
+	if err != nil {
+		fmt.Printf("Ошибка при открытии графа: %v\n", err)
+		os.Exit(1)
+	}
+	defer r.Close()
+
+	var numEdges uint64
+	var totalWeight int64
+	minWeight, maxWeight := int64(0), int64(0)
+	for {
+		e, ok := r.Next()
+		if !ok {
+			break
+		}
+		if numEdges == 0 || e.Weight < minWeight {
+			minWeight = e.Weight
+		}
+		if numEdges == 0 || e.Weight > maxWeight {
+			maxWeight = e.Weight
+		}
+		totalWeight += e.Weight
+		numEdges++
+	}
+	if err := r.Err(); err != nil {
+		fmt.Printf("Ошибка при чтении графа: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("Вершин: %d\n", r.NumVertices())
+	fmt.Printf("Рёбер: %d\n", numEdges)
+	if numEdges > 0 {
+		fmt.Printf("Суммарный вес: %d\n", totalWeight)
+		fmt.Printf("Мин. вес: %d\n", minWeight)
+		fmt.Printf("Макс. вес: %d\n", maxWeight)
+		fmt.Printf("Средний вес: %.2f\n", float64(totalWeight)/float64(numEdges))
+	}
+}
+
+// cmdMST строит минимальное остовное дерево графа алгоритмом Борувки
+// (pkg/boruvka). Рёбра не вычитываются в память целиком: boruvka.MST делает
+// несколько проходов, и каждый проход открывает файл заново через
+// graphio.OpenReader, стримя рёбра через EdgeIterator, — так MST считается и
+// для графов, не помещающихся в RAM. С -workers>1 по несжатому Version1
+// бинарнику вместо этого гоняет boruvka.MSTParallel: он делит каждый проход
+// между workers горутинами через graphio.RandomAccessReader.Split вместо
+// последовательного чтения с диска. Для остальных файлов (сжатых, Version2/3,
+// не binary) произвольный доступ невозможен — команда откатывается на
+// последовательный MST: для binary-файла с сообщением о причине, для
+// остальных форматов молча.
+func cmdMST(args []string) {
+	fs := flag.NewFlagSet("mst", flag.ExitOnError)
+	inputFile := fs.String("i", "", "Имя входного файла графа")
+	inFormat := fs.String("informat", "", "Формат входного файла (binary, text, dimacs, ndjson); по умолчанию — по расширению -i")
+	outputFile := fs.String("o", "", "Имя файла для рёбер MST (по умолчанию — вывод в консоль построчно)")
+	outFormat := fs.String("outformat", "", "Формат выходного файла; по умолчанию — по расширению -o")
+	workers := fs.Int("workers", 1, "Число горутин для параллельного скана прохода (требует несжатый Version1 binary; иначе игнорируется)")
+	v3 := fs.Bool("v3", false, "Писать бинарный выход в Version3 (блочный CRC-framing, без сжатия) вместо Version2+gzip по умолчанию")
+	fs.Parse(args)
+
+	if *inputFile == "" {
+		fmt.Println("Использование: bintotxt mst -i inputfile [-informat F] [-o outputfile] [-outformat F] [-workers N] [-v3]")
+		os.Exit(1)
+	}
+
+	inFmt, err := resolveFormat(*inFormat, *inputFile)
+	if err != nil {
+		fmt.Printf("Ошибка: %v\n", err)
+		os.Exit(1)
+	}
+
+	var mst []graphio.Edge
+	var numVertices uint32
+	done := false
+	if *workers > 1 && inFmt == graphio.FormatBinary {
+		mst, numVertices, err = mstParallel(*inputFile, *workers)
+		if err != nil {
+			fmt.Printf("Параллельный проход недоступен (%v), считаю последовательно.\n", err)
+		} else {
+			done = true
+		}
+	}
+	if !done {
+		numVertices, err = probeNumVertices(inFmt, *inputFile)
+		if err == nil {
+			ro := newReopener(inFmt, *inputFile)
+			mst, err = boruvka.MST(ro.next, int(numVertices))
+			ro.Close()
+		}
+	}
+	if err != nil {
+		fmt.Printf("Ошибка при построении MST: %v\n", err)
+		os.Exit(1)
+	}
+
+	var totalWeight int64
+	for _, e := range mst {
+		totalWeight += e.Weight
+	}
+
+	if *outputFile == "" {
+		for _, e := range mst {
+			fmt.Printf("%d,%d,%d\n", e.From, e.To, e.Weight)
+		}
+		fmt.Printf("Рёбер в MST: %d, суммарный вес: %d\n", len(mst), totalWeight)
+		return
+	}
+
+	outFmt, err := resolveFormat(*outFormat, *outputFile)
+	if err != nil {
+		fmt.Printf("Ошибка: %v\n", err)
+		os.Exit(1)
+	}
+	w, err := graphio.CreateWriter(outFmt, *outputFile, outputOptions(*v3), numVertices, uint64(len(mst)))
+	if err != nil {
+		fmt.Printf("Ошибка при создании выходного файла: %v\n", err)
+		os.Exit(1)
+	}
+	for _, e := range mst {
+		if err := w.WriteEdge(e); err != nil {
+			fmt.Printf("Ошибка при записи ребра: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	if err := w.Close(); err != nil {
+		fmt.Printf("Ошибка при закрытии выходного файла: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("Рёбер в MST: %d, суммарный вес: %d, записано в %s.\n", len(mst), totalWeight, *outputFile)
+}
+
+// cmdVerify проверяет целостность бинарного контейнера графа. В обычном
+// режиме — это graphio.Verify: первое же несовпадение CRC32 блока Version3
+// прерывает проверку и называет смещение повреждённого блока; для более
+// старых версий несовпадение CRC32 footer'а выясняется после полного прохода
+// и сообщается без указания места. В режиме -recover вместо этого открывается
+// NewRecoveringReader, который пропускает повреждённые блоки Version3, и
+// отчёт включает их число — только для них восстановление вообще имеет
+// смысл, у text/dimacs/ndjson нет понятия блока.
+func cmdVerify(args []string) {
+	fs := flag.NewFlagSet("verify", flag.ExitOnError)
+	inputFile := fs.String("i", "", "Имя входного файла графа")
+	recover := fs.Bool("recover", false, "Пропускать повреждённые блоки Version3 вместо остановки на первом")
+	fs.Parse(args)
+
+	if *inputFile == "" {
+		fmt.Println("Использование: bintotxt verify -i inputfile [-recover]")
+		os.Exit(1)
+	}
+
+	if !*recover {
+		if err := graphio.Verify(*inputFile); err != nil {
+			fmt.Printf("Граф повреждён: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println("Граф цел.")
+		return
+	}
+
+	file, err := os.Open(*inputFile)
+	if err != nil {
+		fmt.Printf("Ошибка при открытии файла: %v\n", err)
+		os.Exit(1)
+	}
+	defer file.Close()
+
+	r, err := graphio.NewRecoveringReader(file)
+	if err != nil {
+		fmt.Printf("Ошибка при разборе заголовка: %v\n", err)
+		os.Exit(1)
+	}
+	var numEdges uint64
+	for {
+		if _, ok := r.Next(); !ok {
+			break
+		}
+		numEdges++
+	}
+	if err := r.Err(); err != nil {
+		fmt.Printf("Ошибка при чтении графа: %v\n", err)
+		os.Exit(1)
+	}
+	if skipped := r.SkippedBlocks(); skipped > 0 {
+		fmt.Printf("Восстановлено %d рёбер, пропущено повреждённых блоков: %d.\n", numEdges, skipped)
+	} else {
+		fmt.Printf("Граф цел, рёбер: %d.\n", numEdges)
+	}
+}
+
+// mstParallel строит MST через boruvka.MSTParallel, деля каждый проход между
+// workers горутинами (см. graphio.RandomAccessReader.Split). Возвращает
+// ошибку без изменения файла, если он не подходит для произвольного доступа
+// (не Version1 или сжат) — вызывающий код в этом случае откатывается на
+// последовательный boruvka.MST.
+func mstParallel(path string, workers int) ([]graphio.Edge, uint32, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, 0, err
+	}
+	ra, err := graphio.NewRandomAccessReader(file, info.Size())
+	if err != nil {
+		return nil, 0, err
+	}
+
+	mst, err := boruvka.MSTParallel(ra, workers)
+	if err != nil {
+		return nil, 0, err
+	}
+	return mst, ra.NumVertices(), nil
+}
+
+// probeNumVertices определяет число вершин графа. Для binary/DIMACS формат
+// несёт numVertices в заголовке, так что NumVertices уже точен сразу после
+// OpenReader — лишний потоковый проход по всем рёбрам тут не нужен и обошёлся
+// бы ровно тем лишним O(ребёр) сканом, которого chunk0-3/chunk0-4 и избегали.
+// Для text/ndjson заголовка нет: NumVertices — это максимальный увиденный
+// индекс вершины плюс один (см. их doc-комментарии), и становится точным
+// только после полного прохода, так что для них без него не обойтись.
+func probeNumVertices(format graphio.Format, path string) (uint32, error) {
+	r, err := graphio.OpenReader(format, path)
+	if err != nil {
+		return 0, err
+	}
+	defer r.Close()
+
+	if format == graphio.FormatBinary || format == graphio.FormatDIMACS {
+		return r.NumVertices(), nil
+	}
+
+	for {
+		if _, ok := r.Next(); !ok {
+			break
+		}
+	}
+	if err := r.Err(); err != nil {
+		return 0, err
+	}
+	return r.NumVertices(), nil
+}
+
+// reopener — boruvka.IteratorFactory поверх файла на диске: каждый проход
+// заново открывает path через graphio.OpenReader, вместо того чтобы держать
+// все рёбра в памяти между проходами. boruvka.MST полностью вычитывает
+// предыдущий Reader перед тем, как запросить следующий, поэтому достаточно
+// закрывать предыдущий в начале next; последний закрывается явно через
+// Close после того, как MST вернёт управление.
+type reopener struct {
+	format graphio.Format
+	path   string
+	last   graphio.ReaderCloser
+}
+
+func newReopener(format graphio.Format, path string) *reopener {
+	return &reopener{format: format, path: path}
+}
+
+func (ro *reopener) next() (graphio.EdgeIterator, error) {
+	if ro.last != nil {
+		ro.last.Close()
+	}
+	r, err := graphio.OpenReader(ro.format, ro.path)
+	if err != nil {
+		return nil, err
+	}
+	ro.last = r
+	return r, nil
+}
+
+func (ro *reopener) Close() error {
+	if ro.last == nil {
+		return nil
+	}
+	return ro.last.Close()
+}
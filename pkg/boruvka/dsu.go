@@ -0,0 +1,52 @@
+package boruvka
+
+// dsu — система непересекающихся множеств (union-find) со сжатием путей и
+// объединением по рангу, индексируемая номером вершины (0..n-1).
+type dsu struct {
+	parent []int32
+	rank   []int8
+}
+
+func newDSU(n int) *dsu {
+	d := &dsu{parent: make([]int32, n), rank: make([]int8, n)}
+	for i := range d.parent {
+		d.parent[i] = int32(i)
+	}
+	return d
+}
+
+// Find возвращает представителя множества, содержащего v, сжимая путь.
+func (d *dsu) Find(v int32) int32 {
+	for d.parent[v] != v {
+		d.parent[v] = d.parent[d.parent[v]]
+		v = d.parent[v]
+	}
+	return v
+}
+
+// FindConst возвращает представителя множества, содержащего v, не изменяя
+// структуру (без сжатия путей). Пригодна для параллельного вызова из
+// нескольких горутин, в отличие от Find, которая пишет в parent.
+func (d *dsu) FindConst(v int32) int32 {
+	for d.parent[v] != v {
+		v = d.parent[v]
+	}
+	return v
+}
+
+// Union объединяет множества, представленные a и b (a и b должны быть
+// корнями, т.е. результатами Find). Возвращает true, если объединение
+// произошло (a и b были в разных множествах).
+func (d *dsu) Union(a, b int32) bool {
+	if a == b {
+		return false
+	}
+	if d.rank[a] < d.rank[b] {
+		a, b = b, a
+	}
+	d.parent[b] = a
+	if d.rank[a] == d.rank[b] {
+		d.rank[a]++
+	}
+	return true
+}
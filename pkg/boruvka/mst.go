@@ -0,0 +1,97 @@
+// Package boruvka реализует потоковый, внешнепамятный драйвер алгоритма
+// Борувки поверх graphio.EdgeIterator: каждый проход по рёбрам хранит в
+// памяти только текущее самое дешёвое исходящее ребро на компоненту,
+// O(numComponents), а не весь список рёбер — так MST считается и для
+// графов, не помещающихся в RAM.
+package boruvka
+
+import (
+	"fmt"
+
+	"github.com/MTXIK/Boruvkas/pkg/graphio"
+)
+
+// IteratorFactory производит свежий независимый проход по рёбрам графа.
+// MST вызывает её один раз на каждый проход, потому что EdgeIterator
+// одноразовый: Next необратимо продвигает поток.
+type IteratorFactory func() (graphio.EdgeIterator, error)
+
+// MST строит минимальное остовное дерево алгоритмом Борувки: за каждый
+// проход факторка открывает свежий EdgeIterator, по которому для каждой ещё
+// не объединённой компоненты находится самое дешёвое исходящее ребро;
+// найденные рёбра объединяют компоненты, и так до тех пор, пока не останется
+// одна компонента или очередной проход не добавит ни одного ребра (граф
+// несвязный). Если граф несвязный, возвращается остовный лес — рёбра по тем
+// компонентам, которые удалось объединить.
+func MST(factory IteratorFactory, numVertices int) ([]graphio.Edge, error) {
+	if numVertices <= 1 {
+		return nil, nil
+	}
+
+	uf := newDSU(numVertices)
+	var mst []graphio.Edge
+	numComponents := numVertices
+
+	for numComponents > 1 {
+		it, err := factory()
+		if err != nil {
+			return nil, fmt.Errorf("boruvka: не удалось открыть проход по рёбрам: %v", err)
+		}
+		cheapest := make(map[int32]*graphio.Edge, numComponents)
+		if err := scanCheapest(it, uf.Find, numVertices, cheapest); err != nil {
+			return nil, err
+		}
+
+		merged := contract(uf, cheapest, &mst)
+		if merged == 0 {
+			break // граф несвязный: оставшиеся компоненты ничего друг другу не предложили
+		}
+		numComponents -= merged
+	}
+
+	return mst, nil
+}
+
+// scanCheapest обходит it целиком и для каждой встреченной компоненты
+// запоминает в cheapest самое дешёвое ребро, ведущее в другую компоненту.
+// find передаётся отдельно: последовательный проход использует uf.Find (со
+// сжатием путей), а параллельные воркеры — uf.FindConst, чтобы не писать в
+// общую структуру DSU из нескольких горутин одновременно.
+func scanCheapest(it graphio.EdgeIterator, find func(int32) int32, numVertices int, cheapest map[int32]*graphio.Edge) error {
+	for {
+		e, ok := it.Next()
+		if !ok {
+			break
+		}
+		if e.From < 0 || int(e.From) >= numVertices || e.To < 0 || int(e.To) >= numVertices {
+			return fmt.Errorf("boruvka: ребро ссылается на несуществующую вершину: from=%d, to=%d", e.From, e.To)
+		}
+		ra, rb := find(e.From), find(e.To)
+		if ra == rb {
+			continue
+		}
+		edge := e
+		if cur := cheapest[ra]; cur == nil || edge.Weight < cur.Weight {
+			cheapest[ra] = &edge
+		}
+		if cur := cheapest[rb]; cur == nil || edge.Weight < cur.Weight {
+			cheapest[rb] = &edge
+		}
+	}
+	return it.Err()
+}
+
+// contract объединяет компоненты по найденным в cheapest рёбрам, добавляет
+// их в *mst и возвращает число выполненных объединений.
+func contract(uf *dsu, cheapest map[int32]*graphio.Edge, mst *[]graphio.Edge) int {
+	merged := 0
+	for _, ce := range cheapest {
+		ra, rb := uf.Find(ce.From), uf.Find(ce.To)
+		if !uf.Union(ra, rb) {
+			continue
+		}
+		*mst = append(*mst, *ce)
+		merged++
+	}
+	return merged
+}
@@ -0,0 +1,76 @@
+package boruvka
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/MTXIK/Boruvkas/pkg/graphio"
+)
+
+// MSTParallel — как MST, но каждый проход по рёбрам делится между workers
+// горутинами, каждая из которых сканирует свой непересекающийся диапазон
+// рёбер через отдельный io.SectionReader поверх общего ra (см.
+// graphio.RandomAccessReader.Split). Это требует Version1 без сжатия, так
+// как только там рёбра имеют фиксированный размер и допускают произвольный
+// доступ по индексу.
+func MSTParallel(ra *graphio.RandomAccessReader, workers int) ([]graphio.Edge, error) {
+	numVertices := int(ra.NumVertices())
+	if numVertices <= 1 {
+		return nil, nil
+	}
+	if workers < 1 {
+		workers = 1
+	}
+
+	uf := newDSU(numVertices)
+	var mst []graphio.Edge
+	numComponents := numVertices
+
+	for numComponents > 1 {
+		iters, err := ra.Split(workers)
+		if err != nil {
+			return nil, fmt.Errorf("boruvka: не удалось разбить рёбра на диапазоны: %v", err)
+		}
+
+		locals := make([]map[int32]*graphio.Edge, len(iters))
+		errs := make([]error, len(iters))
+		var wg sync.WaitGroup
+		for i, it := range iters {
+			wg.Add(1)
+			go func(i int, it graphio.EdgeIterator) {
+				defer wg.Done()
+				local := make(map[int32]*graphio.Edge)
+				// uf.FindConst не пишет в DSU, поэтому безопасна для
+				// одновременного вызова из разных горутин.
+				errs[i] = scanCheapest(it, uf.FindConst, numVertices, local)
+				locals[i] = local
+			}(i, it)
+		}
+		wg.Wait()
+		for _, err := range errs {
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		// DSU не меняется внутри одного прохода (воркеры только читают её
+		// через FindConst), так что результаты по одному root из разных
+		// воркеров можно слить простым минимумом по весу.
+		merged := make(map[int32]*graphio.Edge, numComponents)
+		for _, local := range locals {
+			for root, e := range local {
+				if cur := merged[root]; cur == nil || e.Weight < cur.Weight {
+					merged[root] = e
+				}
+			}
+		}
+
+		n := contract(uf, merged, &mst)
+		if n == 0 {
+			break
+		}
+		numComponents -= n
+	}
+
+	return mst, nil
+}
@@ -0,0 +1,115 @@
+package boruvka
+
+import (
+	"bytes"
+	"math/rand"
+	"testing"
+
+	"github.com/MTXIK/Boruvkas/pkg/graphio"
+)
+
+// randomAccessFactory кодирует edges как Version1 (сырые int16-триплеты,
+// единственная версия с произвольным доступом — см. graphio.RandomAccessReader)
+// и оборачивает получившиеся байты в RandomAccessReader.
+func randomAccessFactory(t *testing.T, numVertices uint32, edges []graphio.Edge) *graphio.RandomAccessReader {
+	t.Helper()
+	var buf bytes.Buffer
+	w, err := graphio.NewWriter(&buf, graphio.Options{Version: graphio.Version1}, numVertices, uint64(len(edges)))
+	if err != nil {
+		t.Fatalf("NewWriter: %v", err)
+	}
+	if err := w.WriteAll(edges); err != nil {
+		t.Fatalf("WriteAll: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	data := buf.Bytes()
+
+	ra, err := graphio.NewRandomAccessReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("NewRandomAccessReader: %v", err)
+	}
+	return ra
+}
+
+// TestMSTParallelKnownGraph — тот же учебный граф, что и TestMSTKnownGraph
+// (вес MST = 11, 4 ребра), но пройденный параллельно через MSTParallel.
+func TestMSTParallelKnownGraph(t *testing.T) {
+	edges := []graphio.Edge{
+		{From: 0, To: 1, Weight: 4},
+		{From: 0, To: 2, Weight: 1},
+		{From: 1, To: 2, Weight: 2},
+		{From: 1, To: 3, Weight: 5},
+		{From: 2, To: 3, Weight: 8},
+		{From: 3, To: 4, Weight: 3},
+	}
+
+	for _, workers := range []int{1, 2, 4, 8} {
+		ra := randomAccessFactory(t, 5, edges)
+		mst, err := MSTParallel(ra, workers)
+		if err != nil {
+			t.Fatalf("workers=%d: MSTParallel: %v", workers, err)
+		}
+		if len(mst) != 4 {
+			t.Fatalf("workers=%d: рёбер в MST: %d, хотим 4", workers, len(mst))
+		}
+		if got := weightSum(mst); got != 11 {
+			t.Fatalf("workers=%d: суммарный вес MST: %d, хотим 11", workers, got)
+		}
+	}
+}
+
+// TestMSTParallelMatchesSequential строит случайный связный граф и сверяет
+// MSTParallel (несколько воркеров, делящих каждый проход через
+// RandomAccessReader.Split и читающих общую DSU конкурентно через
+// FindConst) с последовательным MST по суммарному весу и числу рёбер —
+// при неуникальных весах рёбер конкретный набор рёбер MST не обязан
+// совпадать, но его суммарный вес как решения паросочетания матроида обязан.
+// Гоняется с -race в CI, чтобы ловить гонки в конкурентном доступе к DSU.
+func TestMSTParallelMatchesSequential(t *testing.T) {
+	const numVertices = 200
+	rnd := rand.New(rand.NewSource(7))
+	edges := make([]graphio.Edge, 0, numVertices-1+2000)
+	// Гарантируем связность цепочкой 0-1-2-...-numVertices-1, затем добавляем
+	// случайные рёбра поверх неё.
+	for i := 0; i < numVertices-1; i++ {
+		edges = append(edges, graphio.Edge{From: int32(i), To: int32(i + 1), Weight: int64(rnd.Intn(1000))})
+	}
+	for i := 0; i < 2000; i++ {
+		edges = append(edges, graphio.Edge{
+			From:   int32(rnd.Intn(numVertices)),
+			To:     int32(rnd.Intn(numVertices)),
+			Weight: int64(rnd.Intn(1000)),
+		})
+	}
+
+	seqMST, err := MST(bufferFactory(t, numVertices, edges), numVertices)
+	if err != nil {
+		t.Fatalf("MST: %v", err)
+	}
+
+	ra := randomAccessFactory(t, numVertices, edges)
+	parMST, err := MSTParallel(ra, 8)
+	if err != nil {
+		t.Fatalf("MSTParallel: %v", err)
+	}
+
+	if len(parMST) != len(seqMST) {
+		t.Fatalf("MSTParallel вернул %d рёбер, последовательный MST — %d", len(parMST), len(seqMST))
+	}
+	if got, want := weightSum(parMST), weightSum(seqMST); got != want {
+		t.Fatalf("суммарный вес MSTParallel = %d, последовательного MST = %d", got, want)
+	}
+}
+
+func TestMSTParallelSingleVertexReturnsNil(t *testing.T) {
+	ra := randomAccessFactory(t, 1, nil)
+	mst, err := MSTParallel(ra, 4)
+	if err != nil {
+		t.Fatalf("MSTParallel: %v", err)
+	}
+	if mst != nil {
+		t.Fatalf("MSTParallel() = %+v, хотим nil", mst)
+	}
+}
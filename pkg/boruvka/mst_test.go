@@ -0,0 +1,91 @@
+package boruvka
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/MTXIK/Boruvkas/pkg/graphio"
+)
+
+// bufferFactory возвращает IteratorFactory поверх заранее
+// закодированного контейнера: как и реальный CLI-факторка, каждый вызов
+// открывает независимый свежий Reader — только вместо файла тут bytes.Reader
+// поверх одних и тех же, не потребляемых между проходами, байт.
+func bufferFactory(t *testing.T, numVertices uint32, edges []graphio.Edge) IteratorFactory {
+	t.Helper()
+	var buf bytes.Buffer
+	w, err := graphio.NewWriter(&buf, graphio.Options{Version: graphio.Version2}, numVertices, uint64(len(edges)))
+	if err != nil {
+		t.Fatalf("NewWriter: %v", err)
+	}
+	if err := w.WriteAll(edges); err != nil {
+		t.Fatalf("WriteAll: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	data := buf.Bytes()
+
+	return func() (graphio.EdgeIterator, error) {
+		return graphio.NewReader(bytes.NewReader(data))
+	}
+}
+
+func weightSum(edges []graphio.Edge) int64 {
+	var total int64
+	for _, e := range edges {
+		total += e.Weight
+	}
+	return total
+}
+
+// TestMSTKnownGraph строит MST на учебном графе из 5 вершин, где минимальное
+// остовное дерево и его вес известны заранее: {0-2, 1-2, 3-4, 1-3}, вес 11.
+func TestMSTKnownGraph(t *testing.T) {
+	edges := []graphio.Edge{
+		{From: 0, To: 1, Weight: 4},
+		{From: 0, To: 2, Weight: 1},
+		{From: 1, To: 2, Weight: 2},
+		{From: 1, To: 3, Weight: 5},
+		{From: 2, To: 3, Weight: 8},
+		{From: 3, To: 4, Weight: 3},
+	}
+
+	mst, err := MST(bufferFactory(t, 5, edges), 5)
+	if err != nil {
+		t.Fatalf("MST: %v", err)
+	}
+	if len(mst) != 4 {
+		t.Fatalf("рёбер в MST: %d, хотим 4", len(mst))
+	}
+	if got := weightSum(mst); got != 11 {
+		t.Fatalf("суммарный вес MST: %d, хотим 11", got)
+	}
+}
+
+// TestMSTDisconnectedGraphReturnsForest — граф из двух несвязных компонент
+// (0-1 и 2-3) должен вернуть остовный лес из двух рёбер, а не ошибку.
+func TestMSTDisconnectedGraphReturnsForest(t *testing.T) {
+	edges := []graphio.Edge{
+		{From: 0, To: 1, Weight: 1},
+		{From: 2, To: 3, Weight: 1},
+	}
+
+	mst, err := MST(bufferFactory(t, 4, edges), 4)
+	if err != nil {
+		t.Fatalf("MST: %v", err)
+	}
+	if len(mst) != 2 {
+		t.Fatalf("рёбер в остовном лесу: %d, хотим 2", len(mst))
+	}
+}
+
+func TestMSTSingleVertexReturnsNil(t *testing.T) {
+	mst, err := MST(bufferFactory(t, 1, nil), 1)
+	if err != nil {
+		t.Fatalf("MST: %v", err)
+	}
+	if mst != nil {
+		t.Fatalf("MST() = %+v, хотим nil", mst)
+	}
+}
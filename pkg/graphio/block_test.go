@@ -0,0 +1,228 @@
+package graphio
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func TestBlockWriterReaderRoundTrip(t *testing.T) {
+	data := make([]byte, blockSize*2+100) // два полных блока + короткий хвост
+	for i := range data {
+		data[i] = byte(i)
+	}
+
+	var buf bytes.Buffer
+	bw := newBlockWriter(&buf)
+	if _, err := bw.Write(data); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if err := bw.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	br := newBlockReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()), false)
+	got, err := io.ReadAll(br)
+	if err != nil {
+		t.Fatalf("ReadAll: %v", err)
+	}
+	if !bytes.Equal(got, data) {
+		t.Fatalf("прочитано %d байт, записано %d — содержимое разошлось", len(got), len(data))
+	}
+}
+
+func TestBlockReaderDetectsCorruption(t *testing.T) {
+	data := make([]byte, blockSize+50)
+	for i := range data {
+		data[i] = byte(i)
+	}
+	var buf bytes.Buffer
+	bw := newBlockWriter(&buf)
+	if _, err := bw.Write(data); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if err := bw.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	corrupted := buf.Bytes()
+	corrupted[10] ^= 0xFF // портим байт внутри первого (полного) блока
+
+	br := newBlockReader(bytes.NewReader(corrupted), int64(len(corrupted)), false)
+	if _, err := io.ReadAll(br); !errors.Is(err, ErrChecksumMismatch) {
+		t.Fatalf("ReadAll() вернул err=%v, хотим ErrChecksumMismatch", err)
+	}
+}
+
+func TestBlockReaderRecoversFromCorruption(t *testing.T) {
+	data := make([]byte, blockSize+50)
+	for i := range data {
+		data[i] = byte(i)
+	}
+	var buf bytes.Buffer
+	bw := newBlockWriter(&buf)
+	if _, err := bw.Write(data); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if err := bw.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	corrupted := buf.Bytes()
+	corrupted[10] ^= 0xFF // портим байт внутри первого блока, второй остаётся цел
+
+	br := newBlockReader(bytes.NewReader(corrupted), int64(len(corrupted)), true)
+	got, err := io.ReadAll(br)
+	if err != nil {
+		t.Fatalf("ReadAll: %v", err)
+	}
+	if br.skipped != 1 {
+		t.Fatalf("skipped = %d, хотим 1", br.skipped)
+	}
+	if !bytes.Equal(got, data[blockSize:]) {
+		t.Fatalf("после пропуска первого блока получили %d байт, хотим хвост второго блока (%d байт)", len(got), len(data)-blockSize)
+	}
+}
+
+// TestBlockReaderTruncatedTrailerDoesNotPanic воспроизводит обрыв записи
+// прямо внутри 4-байтного crc-трейлера второго блока (достаточно правдоподобно
+// при крэше посреди системного вызова записи): после первого, полностью
+// записанного блока остаётся меньше 4 байт. Раньше это приводило к
+// make([]byte, dataLen) с отрицательным dataLen и панике.
+func TestBlockReaderTruncatedTrailerDoesNotPanic(t *testing.T) {
+	data := make([]byte, blockSize)
+	for i := range data {
+		data[i] = byte(i)
+	}
+	var buf bytes.Buffer
+	bw := newBlockWriter(&buf)
+	if _, err := bw.Write(data); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if err := bw.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	// Один полный блок плюс пара байт недописанного следующего — меньше
+	// одного трейлера.
+	truncated := append(buf.Bytes(), 0xAB, 0xCD)
+
+	t.Run("recover=true returns clean EOF", func(t *testing.T) {
+		br := newBlockReader(bytes.NewReader(truncated), int64(len(truncated)), true)
+		got, err := io.ReadAll(br)
+		if err != nil {
+			t.Fatalf("ReadAll: %v", err)
+		}
+		if !bytes.Equal(got, data) {
+			t.Fatalf("восстановлено %d байт, хотим ровно первый блок (%d байт)", len(got), len(data))
+		}
+	})
+
+	t.Run("recover=false returns a clean error, not a panic", func(t *testing.T) {
+		br := newBlockReader(bytes.NewReader(truncated), int64(len(truncated)), false)
+		if _, err := io.ReadAll(br); err == nil {
+			t.Fatal("ReadAll() = nil, хотим ошибку на обрубленном трейлере")
+		}
+	})
+}
+
+// genV3Edges строит отсортированный по (from, to) граф — так же, как его
+// отсортировал бы Writer.WriteAll для Version2/3.
+func genV3Edges(numVertices, numEdges int) []Edge {
+	rnd := rand.New(rand.NewSource(42))
+	edges := make([]Edge, numEdges)
+	for i := range edges {
+		edges[i] = Edge{
+			From:   int32(rnd.Intn(numVertices)),
+			To:     int32(rnd.Intn(numVertices)),
+			Weight: int64(rnd.Intn(1000)),
+		}
+	}
+	sort.Slice(edges, func(i, j int) bool {
+		if edges[i].From != edges[j].From {
+			return edges[i].From < edges[j].From
+		}
+		return edges[i].To < edges[j].To
+	})
+	return edges
+}
+
+func TestVersion3RoundTrip(t *testing.T) {
+	edges := genV3Edges(500, 50000) // заведомо больше одного blockSize
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, Options{Version: Version3}, 500, uint64(len(edges)))
+	if err != nil {
+		t.Fatalf("NewWriter: %v", err)
+	}
+	if err := w.WriteAll(edges); err != nil {
+		t.Fatalf("WriteAll: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	r, err := NewReader(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("NewReader: %v", err)
+	}
+	got, err := r.ReadAll()
+	if err != nil {
+		t.Fatalf("ReadAll: %v", err)
+	}
+	if len(got) != len(edges) {
+		t.Fatalf("прочитано %d рёбер, записано %d", len(got), len(edges))
+	}
+	for i := range edges {
+		if got[i] != edges[i] {
+			t.Fatalf("ребро %d: получили %+v, хотим %+v", i, got[i], edges[i])
+		}
+	}
+	if r.SkippedBlocks() != 0 {
+		t.Fatalf("SkippedBlocks() = %d на целом файле, хотим 0", r.SkippedBlocks())
+	}
+}
+
+// TestVersion3RecoverAfterCrash воспроизводит ровно тот сценарий, который и
+// был причиной признания фичи: Writer пишет рёбра и ни разу не вызывает
+// Close (крэш посреди записи, footer так и не появился). NewRecoveringReader
+// должен дочитать всё, что успело записаться, вернув nil-ошибку, а не
+// падать на io.ErrUnexpectedEOF оборванного varint'а в хвосте потока.
+func TestVersion3RecoverAfterCrash(t *testing.T) {
+	edges := genV3Edges(500, 50000)
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, Options{Version: Version3}, 500, uint64(len(edges)))
+	if err != nil {
+		t.Fatalf("NewWriter: %v", err)
+	}
+	if err := w.WriteAll(edges); err != nil {
+		t.Fatalf("WriteAll: %v", err)
+	}
+	// Намеренно не вызываем w.Close() — footer никогда не появится, а
+	// последний неполный блок так и останется в буфере Writer'а и не попадёт
+	// на "диск".
+
+	data := buf.Bytes()
+	if len(data) <= headerSize+footerSize {
+		t.Fatalf("в тесте недостаточно данных для сценария: %d байт", len(data))
+	}
+
+	r, err := NewRecoveringReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("NewRecoveringReader: %v", err)
+	}
+	got, err := r.ReadAll()
+	if err != nil {
+		t.Fatalf("ReadAll() после незакрытого writer'а вернул err=%v, хотим nil", err)
+	}
+	if len(got) == 0 {
+		t.Fatal("восстановлено 0 рёбер")
+	}
+	if len(got) > len(edges) {
+		t.Fatalf("восстановлено %d рёбер — больше, чем вообще было записано (%d)", len(got), len(edges))
+	}
+	for i := range got {
+		if got[i] != edges[i] {
+			t.Fatalf("ребро %d: получили %+v, хотим %+v (восстановленный префикс должен совпадать с исходным)", i, got[i], edges[i])
+		}
+	}
+}
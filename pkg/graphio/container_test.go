@@ -0,0 +1,129 @@
+package graphio
+
+import (
+	"bytes"
+	"testing"
+)
+
+// roundTrip пишет edges в контейнер версии version и тут же читает их обратно
+// через NewReader, сверяя и сами рёбра, и NumVertices.
+func roundTrip(t *testing.T, version uint8, numVertices uint32, edges []Edge) []Edge {
+	t.Helper()
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, Options{Version: version}, numVertices, uint64(len(edges)))
+	if err != nil {
+		t.Fatalf("NewWriter: %v", err)
+	}
+	for _, e := range edges {
+		if err := w.WriteEdge(e); err != nil {
+			t.Fatalf("WriteEdge: %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	r, err := NewReader(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("NewReader: %v", err)
+	}
+	if r.NumVertices() != numVertices {
+		t.Fatalf("NumVertices() = %d, хотим %d", r.NumVertices(), numVertices)
+	}
+	got, err := r.ReadAll()
+	if err != nil {
+		t.Fatalf("ReadAll: %v", err)
+	}
+	if len(got) != len(edges) {
+		t.Fatalf("прочитано %d рёбер, записано %d", len(got), len(edges))
+	}
+	for i := range edges {
+		if got[i] != edges[i] {
+			t.Fatalf("ребро %d: получили %+v, хотим %+v", i, got[i], edges[i])
+		}
+	}
+	return got
+}
+
+func TestContainerRoundTripVersion1(t *testing.T) {
+	edges := []Edge{
+		{From: 0, To: 1, Weight: 4},
+		{From: 0, To: 2, Weight: -1},
+		{From: 1, To: 2, Weight: 2},
+	}
+	roundTrip(t, Version1, 3, edges)
+}
+
+func TestContainerRoundTripVersion2(t *testing.T) {
+	edges := []Edge{
+		{From: 0, To: 1, Weight: 4},
+		{From: 0, To: 2, Weight: -1},
+		{From: 100000, To: 100001, Weight: 2}, // проверяем, что Version2 снимает предел int16
+	}
+	roundTrip(t, Version2, 100002, edges)
+}
+
+func TestContainerFooterCRCMismatchDetected(t *testing.T) {
+	var buf bytes.Buffer
+	edges := []Edge{{From: 0, To: 1, Weight: 4}, {From: 1, To: 2, Weight: 2}}
+	w, err := NewWriter(&buf, Options{Version: Version2}, 3, uint64(len(edges)))
+	if err != nil {
+		t.Fatalf("NewWriter: %v", err)
+	}
+	if err := w.WriteAll(edges); err != nil {
+		t.Fatalf("WriteAll: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	data := buf.Bytes()
+	// Портим один бит полезной нагрузки, сразу после заголовка — не
+	// затрагивая старший (continuation) бит varint'а, чтобы повреждение
+	// всплыло как несовпадение CRC32 footer'а, а не как оборванный поток.
+	data[headerSize] ^= 0x02
+
+	r, err := NewReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("NewReader: %v", err)
+	}
+	_, err = r.ReadAll()
+	if err != ErrChecksumMismatch {
+		t.Fatalf("ReadAll() вернул err=%v, хотим ErrChecksumMismatch", err)
+	}
+}
+
+func TestContainerGzipRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	edges := []Edge{{From: 0, To: 1, Weight: 4}, {From: 1, To: 2, Weight: -7}}
+	w, err := NewWriter(&buf, Options{Version: Version2, Gzip: true}, 3, uint64(len(edges)))
+	if err != nil {
+		t.Fatalf("NewWriter: %v", err)
+	}
+	if err := w.WriteAll(edges); err != nil {
+		t.Fatalf("WriteAll: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	r, err := NewReader(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("NewReader: %v", err)
+	}
+	got, err := r.ReadAll()
+	if err != nil {
+		t.Fatalf("ReadAll: %v", err)
+	}
+	if len(got) != len(edges) || got[0] != edges[0] || got[1] != edges[1] {
+		t.Fatalf("получили %+v, хотим %+v", got, edges)
+	}
+}
+
+func TestNewWriterRejectsVersion3WithGzip(t *testing.T) {
+	var buf bytes.Buffer
+	_, err := NewWriter(&buf, Options{Version: Version3, Gzip: true}, 3, 0)
+	if err != ErrBlockFramingRequiresUncompressed {
+		t.Fatalf("NewWriter() вернул err=%v, хотим ErrBlockFramingRequiresUncompressed", err)
+	}
+}
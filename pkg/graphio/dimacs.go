@@ -0,0 +1,118 @@
+package graphio
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// dimacsWriter пишет граф в DIMACS shortest-path challenge формате: строка
+// задачи "p sp n m", затем по одной строке "a u v w" на ребро. DIMACS
+// нумерует вершины с единицы, поэтому индексы сдвигаются на +1 при записи.
+type dimacsWriter struct {
+	w    *bufio.Writer
+	file *os.File
+}
+
+func newDimacsWriter(file *os.File, numVertices uint32, numEdges uint64) (*dimacsWriter, error) {
+	w := bufio.NewWriter(file)
+	if _, err := fmt.Fprintf(w, "p sp %d %d\n", numVertices, numEdges); err != nil {
+		return nil, fmt.Errorf("graphio: не удалось записать problem-строку DIMACS: %v", err)
+	}
+	return &dimacsWriter{w: w, file: file}, nil
+}
+
+func (dw *dimacsWriter) WriteEdge(e Edge) error {
+	_, err := fmt.Fprintf(dw.w, "a %d %d %d\n", e.From+1, e.To+1, e.Weight)
+	if err != nil {
+		return fmt.Errorf("graphio: не удалось записать arc-строку DIMACS: %v", err)
+	}
+	return nil
+}
+
+func (dw *dimacsWriter) Close() error {
+	if err := dw.w.Flush(); err != nil {
+		return fmt.Errorf("graphio: не удалось сбросить буфер: %v", err)
+	}
+	return dw.file.Close()
+}
+
+// dimacsReader читает DIMACS shortest-path challenge формат: пропускает
+// комментарии "c ...", разбирает problem-строку "p sp n m" для numVertices
+// и отдаёт рёбра из строк "a u v w" (переводя индексы вершин обратно в
+// нумерацию с нуля). Прочие директивы ("n ..." — источник/сток) игнорируются.
+type dimacsReader struct {
+	file        *os.File
+	sc          *bufio.Scanner
+	numVertices uint32
+	err         error
+	done        bool
+}
+
+func newDimacsReader(file *os.File) (*dimacsReader, error) {
+	sc := bufio.NewScanner(file)
+	dr := &dimacsReader{file: file, sc: sc}
+
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "c") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 3 || fields[0] != "p" {
+			file.Close()
+			return nil, fmt.Errorf("graphio: ожидалась problem-строка DIMACS ('p sp n m'), получено: %q", line)
+		}
+		n, err := strconv.ParseUint(fields[2], 10, 32)
+		if err != nil {
+			file.Close()
+			return nil, fmt.Errorf("graphio: неверная problem-строка DIMACS: %q", line)
+		}
+		dr.numVertices = uint32(n)
+		break
+	}
+	if err := sc.Err(); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("graphio: не удалось прочитать DIMACS-файл: %v", err)
+	}
+	return dr, nil
+}
+
+func (dr *dimacsReader) Next() (Edge, bool) {
+	if dr.done || dr.err != nil {
+		return Edge{}, false
+	}
+	for dr.sc.Scan() {
+		line := strings.TrimSpace(dr.sc.Text())
+		if line == "" || strings.HasPrefix(line, "c") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if fields[0] != "a" {
+			continue
+		}
+		if len(fields) != 4 {
+			dr.err = fmt.Errorf("graphio: неверная arc-строка DIMACS: %q", line)
+			return Edge{}, false
+		}
+		u, err1 := strconv.ParseInt(fields[1], 10, 32)
+		v, err2 := strconv.ParseInt(fields[2], 10, 32)
+		w, err3 := strconv.ParseInt(fields[3], 10, 64)
+		if err1 != nil || err2 != nil || err3 != nil {
+			dr.err = fmt.Errorf("graphio: не удалось разобрать arc-строку %q", line)
+			return Edge{}, false
+		}
+		return Edge{From: int32(u - 1), To: int32(v - 1), Weight: w}, true
+	}
+	dr.done = true
+	dr.err = dr.sc.Err()
+	return Edge{}, false
+}
+
+func (dr *dimacsReader) Err() error { return dr.err }
+
+func (dr *dimacsReader) NumVertices() uint32 { return dr.numVertices }
+
+func (dr *dimacsReader) Close() error { return dr.file.Close() }
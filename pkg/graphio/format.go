@@ -0,0 +1,188 @@
+package graphio
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Format — один из форматов хранения графа, которые умеет читать/писать CLI.
+type Format int
+
+// Поддерживаемые форматы.
+const (
+	FormatBinary Format = iota // версионированный контейнер graphio (see graphio.go)
+	FormatText                // простые строки "from,to,weight"
+	FormatDIMACS              // DIMACS shortest-path challenge: "p sp n m" + "a u v w"
+	FormatNDJSON              // newline-delimited JSON: {"from":.,"to":.,"weight":.}
+)
+
+func (f Format) String() string {
+	switch f {
+	case FormatBinary:
+		return "binary"
+	case FormatText:
+		return "text"
+	case FormatDIMACS:
+		return "dimacs"
+	case FormatNDJSON:
+		return "ndjson"
+	default:
+		return "unknown"
+	}
+}
+
+// ErrUnsupportedFormat возвращается для нераспознанного имени/расширения формата.
+var ErrUnsupportedFormat = fmt.Errorf("graphio: неизвестный формат графа")
+
+// ParseFormat разбирает имя формата, переданное явно через флаг CLI
+// (-informat/-outformat).
+func ParseFormat(s string) (Format, error) {
+	switch strings.ToLower(s) {
+	case "binary", "bin":
+		return FormatBinary, nil
+	case "text", "txt":
+		return FormatText, nil
+	case "dimacs", "gr":
+		return FormatDIMACS, nil
+	case "ndjson", "jsonl":
+		return FormatNDJSON, nil
+	default:
+		return 0, fmt.Errorf("%w: %q", ErrUnsupportedFormat, s)
+	}
+}
+
+// DetectFormat определяет формат по расширению имени файла.
+func DetectFormat(path string) (Format, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".bin", ".grb", ".brvk":
+		return FormatBinary, nil
+	case ".txt":
+		return FormatText, nil
+	case ".gr", ".dimacs":
+		return FormatDIMACS, nil
+	case ".ndjson", ".jsonl":
+		return FormatNDJSON, nil
+	default:
+		return 0, fmt.Errorf("%w: не удалось определить формат по расширению %q, укажите его явно", ErrUnsupportedFormat, path)
+	}
+}
+
+// ReaderCloser — то, что умеет отдавать CLI-командам OpenReader: потоковый
+// обход рёбер плюс доступ к количеству вершин и закрытие нижележащего файла.
+type ReaderCloser interface {
+	EdgeIterator
+	NumVertices() uint32
+	io.Closer
+}
+
+// WriterCloser — то, что умеет отдавать CreateWriter: запись рёбер по одному
+// и финализация (footer/flush) с закрытием нижележащего файла.
+type WriterCloser interface {
+	WriteEdge(Edge) error
+	io.Closer
+}
+
+// OpenReader открывает path и возвращает ReaderCloser нужного формата.
+func OpenReader(format Format, path string) (ReaderCloser, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("graphio: не удалось открыть %s: %v", path, err)
+	}
+
+	switch format {
+	case FormatBinary:
+		r, err := NewReader(file)
+		if err != nil {
+			file.Close()
+			return nil, err
+		}
+		return &binaryReaderCloser{Reader: r, file: file}, nil
+	case FormatText:
+		return newTextReader(file), nil
+	case FormatDIMACS:
+		r, err := newDimacsReader(file)
+		if err != nil {
+			return nil, err
+		}
+		return r, nil
+	case FormatNDJSON:
+		return newNDJSONReader(file), nil
+	default:
+		file.Close()
+		return nil, fmt.Errorf("%w: %v", ErrUnsupportedFormat, format)
+	}
+}
+
+// binaryReaderCloser подмешивает закрытие файла к *Reader, который сам по
+// себе не владеет переданным io.ReadSeeker.
+type binaryReaderCloser struct {
+	*Reader
+	file *os.File
+}
+
+func (b *binaryReaderCloser) Close() error { return b.file.Close() }
+
+// binaryWriterCloser подмешивает закрытие файла к *Writer, который сам по
+// себе не владеет переданным io.Writer: Writer.Close только дописывает
+// footer (и flush'ит gzip/block), не зная, что под ним вообще файл.
+type binaryWriterCloser struct {
+	*Writer
+	file *os.File
+}
+
+func (b *binaryWriterCloser) Close() error {
+	if err := b.Writer.Close(); err != nil {
+		return err
+	}
+	return b.file.Close()
+}
+
+// CreateWriter создаёт (перезаписывая) path и возвращает WriterCloser
+// нужного формата. numVertices/numEdges обязательны для форматов, пишущих
+// заголовок вперёд (binary, DIMACS); text и ndjson их игнорируют.
+func CreateWriter(format Format, path string, opts Options, numVertices uint32, numEdges uint64) (WriterCloser, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("graphio: не удалось создать %s: %v", path, err)
+	}
+
+	switch format {
+	case FormatBinary:
+		w, err := NewWriter(file, opts, numVertices, numEdges)
+		if err != nil {
+			file.Close()
+			return nil, err
+		}
+		return &binaryWriterCloser{Writer: w, file: file}, nil
+	case FormatText:
+		return newTextWriter(file), nil
+	case FormatDIMACS:
+		w, err := newDimacsWriter(file, numVertices, numEdges)
+		if err != nil {
+			return nil, err
+		}
+		return w, nil
+	case FormatNDJSON:
+		return newNDJSONWriter(file), nil
+	default:
+		file.Close()
+		return nil, fmt.Errorf("%w: %v", ErrUnsupportedFormat, format)
+	}
+}
+
+// ReadAll вычитывает все рёбра из произвольного EdgeIterator — для
+// source-форматов без собственного среза (text, DIMACS, ndjson).
+func ReadAll(it EdgeIterator) ([]Edge, error) {
+	var edges []Edge
+	for {
+		e, ok := it.Next()
+		if !ok {
+			break
+		}
+		edges = append(edges, e)
+	}
+	return edges, it.Err()
+}
@@ -0,0 +1,165 @@
+package graphio
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeAndReopen пишет edges через CreateWriter(format, ...) во временный
+// файл и открывает его заново через OpenReader — как и реальный CLI, который
+// никогда не держит Writer и Reader на одном файле одновременно.
+func writeAndReopen(t *testing.T, format Format, name string, numVertices uint32, edges []Edge) ReaderCloser {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	w, err := CreateWriter(format, path, DefaultOptions(), numVertices, uint64(len(edges)))
+	if err != nil {
+		t.Fatalf("CreateWriter: %v", err)
+	}
+	for _, e := range edges {
+		if err := w.WriteEdge(e); err != nil {
+			t.Fatalf("WriteEdge: %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	r, err := OpenReader(format, path)
+	if err != nil {
+		t.Fatalf("OpenReader: %v", err)
+	}
+	return r
+}
+
+func assertEdges(t *testing.T, r ReaderCloser, edges []Edge) {
+	t.Helper()
+	defer r.Close()
+	got, err := ReadAll(r)
+	if err != nil {
+		t.Fatalf("ReadAll: %v", err)
+	}
+	if len(got) != len(edges) {
+		t.Fatalf("прочитано %d рёбер, записано %d", len(got), len(edges))
+	}
+	for i := range edges {
+		if got[i] != edges[i] {
+			t.Fatalf("ребро %d: получили %+v, хотим %+v", i, got[i], edges[i])
+		}
+	}
+}
+
+func TestTextFormatRoundTrip(t *testing.T) {
+	edges := []Edge{{From: 0, To: 1, Weight: 4}, {From: 1, To: 2, Weight: -3}}
+	r := writeAndReopen(t, FormatText, "g.txt", 3, edges)
+	assertEdges(t, r, edges)
+}
+
+func TestDIMACSFormatRoundTrip(t *testing.T) {
+	edges := []Edge{{From: 0, To: 1, Weight: 4}, {From: 1, To: 2, Weight: 3}}
+	r := writeAndReopen(t, FormatDIMACS, "g.gr", 3, edges)
+	if r.NumVertices() != 3 {
+		t.Fatalf("NumVertices() = %d, хотим 3", r.NumVertices())
+	}
+	assertEdges(t, r, edges)
+}
+
+func TestNDJSONFormatRoundTrip(t *testing.T) {
+	edges := []Edge{{From: 0, To: 1, Weight: 4}, {From: 1, To: 2, Weight: -3}}
+	r := writeAndReopen(t, FormatNDJSON, "g.ndjson", 3, edges)
+	assertEdges(t, r, edges)
+}
+
+func TestBinaryFormatRoundTrip(t *testing.T) {
+	edges := []Edge{{From: 0, To: 1, Weight: 4}, {From: 1, To: 2, Weight: -3}}
+	r := writeAndReopen(t, FormatBinary, "g.grb", 3, edges)
+	if r.NumVertices() != 3 {
+		t.Fatalf("NumVertices() = %d, хотим 3", r.NumVertices())
+	}
+	assertEdges(t, r, edges)
+}
+
+// TestCreateWriterBinaryClosesFile — регрессия на утечку fd: CreateWriter
+// открывает *os.File сам, и Writer.Close (пишет только footer/flush
+// gzip/block) о нём не знает, так что закрывать файл обязан WriterCloser,
+// который CreateWriter вернул.
+func TestCreateWriterBinaryClosesFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "g.grb")
+	w, err := CreateWriter(FormatBinary, path, DefaultOptions(), 3, 1)
+	if err != nil {
+		t.Fatalf("CreateWriter: %v", err)
+	}
+	if err := w.WriteEdge(Edge{From: 0, To: 1, Weight: 4}); err != nil {
+		t.Fatalf("WriteEdge: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	bw, ok := w.(*binaryWriterCloser)
+	if !ok {
+		t.Fatalf("CreateWriter(FormatBinary) вернул %T, хотим *binaryWriterCloser", w)
+	}
+	if _, err := bw.file.Write([]byte{0}); err == nil {
+		t.Fatal("запись в файл после Close() прошла без ошибки — файл остался открыт")
+	}
+}
+
+func TestTextFormatSkipsBlankAndCommentLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "g.txt")
+	content := "# comment\n\n0,1,4\n\n# another comment\n1,2,-3\n"
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	r, err := OpenReader(FormatText, path)
+	if err != nil {
+		t.Fatalf("OpenReader: %v", err)
+	}
+	defer r.Close()
+	got, err := ReadAll(r)
+	if err != nil {
+		t.Fatalf("ReadAll: %v", err)
+	}
+	want := []Edge{{From: 0, To: 1, Weight: 4}, {From: 1, To: 2, Weight: -3}}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Fatalf("получили %+v, хотим %+v", got, want)
+	}
+}
+
+func TestDIMACSFormatRejectsBadProblemLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bad.gr")
+	if err := os.WriteFile(path, []byte("not a problem line\n"), 0o644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	if _, err := OpenReader(FormatDIMACS, path); err == nil {
+		t.Fatal("OpenReader() = nil, хотим ошибку на неверной problem-строке")
+	}
+}
+
+func TestDetectFormatByExtension(t *testing.T) {
+	cases := map[string]Format{
+		"g.bin":    FormatBinary,
+		"g.grb":    FormatBinary,
+		"g.brvk":   FormatBinary,
+		"g.txt":    FormatText,
+		"g.gr":     FormatDIMACS,
+		"g.dimacs": FormatDIMACS,
+		"g.ndjson": FormatNDJSON,
+		"g.jsonl":  FormatNDJSON,
+	}
+	for path, want := range cases {
+		got, err := DetectFormat(path)
+		if err != nil {
+			t.Fatalf("DetectFormat(%q): %v", path, err)
+		}
+		if got != want {
+			t.Fatalf("DetectFormat(%q) = %v, хотим %v", path, got, want)
+		}
+	}
+
+	if _, err := DetectFormat("g.unknown"); err == nil {
+		t.Fatal("DetectFormat() = nil, хотим ошибку на неизвестном расширении")
+	}
+}
@@ -0,0 +1,110 @@
+// Package graphio реализует версионированный контейнерный формат файлов
+// графа для Boruvkas: заголовок с магической последовательностью, опциональное
+// сжатие полезной нагрузки и завершающий footer с CRC32 несжатой полезной
+// нагрузки. Footer находится по смещению length-8 и читается при открытии,
+// а сверка CRC32 выполняется после полного прохода по рёбрам.
+package graphio
+
+import "errors"
+
+// Magic — сигнатура, с которой начинается любой файл нового формата.
+const Magic = "BRVK"
+
+// Версии формата.
+const (
+	// Version0 — легаси-формат: сырой int16 (count + триплеты), без магии
+	// и без footer'а. Поддерживается только на чтение.
+	Version0 uint8 = 0
+	// Version1 — первая версия контейнера: магия + заголовок + сырые
+	// триплеты (from, to, weight) int16 + footer с CRC32.
+	Version1 uint8 = 1
+	// Version2 — как Version1, но полезная нагрузка кодируется varint'ами
+	// с дельтами вместо сырых int16-триплетов, что снимает ограничение в
+	// 32767 вершин и существенно уменьшает файл на отсортированных рёбрах.
+	// См. encodeEdgeV2/decodeEdgeV2.
+	Version2 uint8 = 2
+	// Version3 — как Version2 (varint+дельты), но полезная нагрузка режется
+	// на блоки по blockSize с crc32.IEEE после каждого блока (см. block.go).
+	// Это даёт точки восстановления внутри файла: обрыв записи на
+	// многогигабайтном графе портит один блок, а не весь файл. Несовместима
+	// со сжатием (см. ErrBlockFramingRequiresUncompressed) — частые точки
+	// восстановления не совмещаются с непрерывным потоком компрессора.
+	Version3 uint8 = 3
+)
+
+// Биты флагов заголовка.
+const (
+	FlagGzip     uint8 = 1 << 0 // полезная нагрузка сжата gzip (best speed)
+	FlagSnappy   uint8 = 1 << 1 // полезная нагрузка сжата snappy
+	FlagDirected uint8 = 1 << 2 // граф ориентированный
+)
+
+// headerSize — длина заголовка версии 1 в байтах: magic(4) + version(1) +
+// flags(1) + numVertices(4) + numEdges(8).
+const headerSize = 4 + 1 + 1 + 4 + 8
+
+// footerSize — длина завершающего блока: crc32(4) + headerOffset(4).
+const footerSize = 4 + 4
+
+// Edge — ребро графа. From/To расширены до int32, а Weight — до int64, чтобы
+// не упираться в предел 32767 вершин исходного int16-формата: реальные графы
+// (дорожные сети, веб-граф) легко его превышают.
+type Edge struct {
+	From   int32
+	To     int32
+	Weight int64
+}
+
+// Options управляет тем, как Writer сериализует граф.
+type Options struct {
+	// Version — версия формата payload'а: Version1 (сырые int16-триплеты,
+	// обратная совместимость), Version2 (varint+дельты) или Version3
+	// (varint+дельты с блочным CRC-framing). Ноль означает Version2.
+	Version uint8
+	// Gzip включает сжатие полезной нагрузки gzip'ом на уровне best speed.
+	Gzip bool
+	// Snappy включает сжатие полезной нагрузки snappy. Пока не реализовано.
+	Snappy bool
+	// Directed помечает граф как ориентированный (бит FlagDirected).
+	Directed bool
+}
+
+// DefaultOptions возвращает настройки, используемые CLI по умолчанию:
+// Version2 (varint+дельты), gzip best speed, граф неориентированный.
+func DefaultOptions() Options {
+	return Options{Version: Version2, Gzip: true}
+}
+
+// ErrBadMagic возвращается, когда файл не начинается с Magic и не похож на
+// легаси int16-формат.
+var ErrBadMagic = errors.New("graphio: неверная сигнатура файла")
+
+// ErrUnsupportedVersion возвращается для неизвестного байта версии.
+var ErrUnsupportedVersion = errors.New("graphio: неподдерживаемая версия формата")
+
+// ErrChecksumMismatch возвращается, когда CRC32 из footer'а не совпадает с
+// контрольной суммой прочитанной полезной нагрузки.
+var ErrChecksumMismatch = errors.New("graphio: контрольная сумма не совпадает, файл повреждён")
+
+// ErrSnappyUnsupported возвращается, когда запрошено snappy-сжатие: формат
+// его описывает (FlagSnappy), но кодек пока не подключён в это дерево.
+var ErrSnappyUnsupported = errors.New("graphio: snappy ещё не реализован")
+
+// ErrOutOfRange возвращается Version1-энкодером, когда ребро не помещается в
+// int16 (используйте Version2 для графов крупнее 32767 вершин).
+var ErrOutOfRange = errors.New("graphio: ребро не помещается в int16, нужен Version2")
+
+// ErrBlockFramingRequiresUncompressed возвращается, когда Version3
+// (блочный CRC-framing) запрошена вместе с Gzip/Snappy: compress-поток
+// непрерывен и не даёт восстанавливаемых по отдельности блоков.
+var ErrBlockFramingRequiresUncompressed = errors.New("graphio: Version3 (блочный CRC-framing) несовместима со сжатием, отключите Gzip/Snappy")
+
+// EdgeIterator — потоковый обход рёбер графа без загрузки всего файла в
+// память: Next возвращает очередное ребро и true, пока не дочитан до конца
+// или не встретилась ошибка, после чего возвращает (Edge{}, false) и Err
+// сообщает причину (nil, если дочитано успешно). *Reader и итераторы
+// RandomAccessReader реализуют этот интерфейс.
+type EdgeIterator interface {
+	Next() (Edge, bool)
+	Err() error
+}
@@ -0,0 +1,91 @@
+package graphio
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// jsonEdge — JSON-представление одного ребра для формата ndjson.
+type jsonEdge struct {
+	From   int32 `json:"from"`
+	To     int32 `json:"to"`
+	Weight int64 `json:"weight"`
+}
+
+// ndjsonWriter пишет по одному JSON-объекту на строку.
+type ndjsonWriter struct {
+	w    *bufio.Writer
+	enc  *json.Encoder
+	file *os.File
+}
+
+func newNDJSONWriter(file *os.File) *ndjsonWriter {
+	w := bufio.NewWriter(file)
+	return &ndjsonWriter{w: w, enc: json.NewEncoder(w), file: file}
+}
+
+func (nw *ndjsonWriter) WriteEdge(e Edge) error {
+	if err := nw.enc.Encode(jsonEdge{From: e.From, To: e.To, Weight: e.Weight}); err != nil {
+		return fmt.Errorf("graphio: не удалось записать строку ndjson: %v", err)
+	}
+	return nil
+}
+
+func (nw *ndjsonWriter) Close() error {
+	if err := nw.w.Flush(); err != nil {
+		return fmt.Errorf("graphio: не удалось сбросить буфер: %v", err)
+	}
+	return nw.file.Close()
+}
+
+// ndjsonReader читает по одному JSON-объекту на строку. Как и у text-формата,
+// NumVertices — это максимальный увиденный индекс вершины плюс один,
+// становится точным только после полного прохода.
+type ndjsonReader struct {
+	file      *os.File
+	sc        *bufio.Scanner
+	maxVertex int32
+	err       error
+	done      bool
+}
+
+func newNDJSONReader(file *os.File) *ndjsonReader {
+	return &ndjsonReader{file: file, sc: bufio.NewScanner(file)}
+}
+
+func (nr *ndjsonReader) Next() (Edge, bool) {
+	if nr.done || nr.err != nil {
+		return Edge{}, false
+	}
+	for nr.sc.Scan() {
+		line := strings.TrimSpace(nr.sc.Text())
+		if line == "" {
+			continue
+		}
+		var je jsonEdge
+		if err := json.Unmarshal([]byte(line), &je); err != nil {
+			nr.err = fmt.Errorf("graphio: не удалось разобрать строку ndjson %q: %v", line, err)
+			return Edge{}, false
+		}
+		e := Edge{From: je.From, To: je.To, Weight: je.Weight}
+		if e.From > nr.maxVertex {
+			nr.maxVertex = e.From
+		}
+		if e.To > nr.maxVertex {
+			nr.maxVertex = e.To
+		}
+		return e, true
+	}
+	nr.done = true
+	nr.err = nr.sc.Err()
+	return Edge{}, false
+}
+
+func (nr *ndjsonReader) Err() error { return nr.err }
+
+func (nr *ndjsonReader) NumVertices() uint32 { return uint32(nr.maxVertex + 1) }
+
+func (nr *ndjsonReader) Close() error { return nr.file.Close() }
@@ -0,0 +1,290 @@
+package graphio
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"io"
+)
+
+// Reader читает граф, записанный в любом поддерживаемом формате: Version0
+// (легаси int16, без footer'а), Version1 (контейнер с сырыми int16-триплетами),
+// Version2 (контейнер с varint+дельтами) или Version3 (как Version2, но с
+// crc32 после каждого блока, см. NewRecoveringReader).
+type Reader struct {
+	version     uint8
+	flags       uint8
+	numVertices uint32
+	numEdges    uint64 // для Version0 заранее неизвестно, остаётся 0
+	legacy      bool
+
+	payload     io.Reader
+	byteReader  *bufio.Reader // для Version2/Version3 (binary.ReadUvarint требует io.ByteReader)
+	delta       edgeDeltaState
+	gz          *gzip.Reader
+	block       *blockReader // для Version3
+	crc         uint32
+	expectedCRC uint32
+	haveFooter  bool
+
+	read uint64 // рёбер уже отдано через Next
+	done bool
+	err  error
+}
+
+// NewReader определяет версию формата по первым байтам файла и возвращает
+// Reader, готовый отдавать рёбра через Next/ReadAll. Для контейнерных версий
+// footer вычитывается сразу же, но CRC32 из него сверяется только после
+// того, как Next отдаст последнее ребро: несовпадение всплывает через Err
+// (или ошибку ReadAll), а не из NewReader. Для Version3 первый же
+// повреждённый блок прерывает чтение ошибкой — чтобы
+// пропускать такие блоки и восстанавливать остальное, используйте
+// NewRecoveringReader.
+func NewReader(r io.ReadSeeker) (*Reader, error) {
+	return newReader(r, false)
+}
+
+// NewRecoveringReader — как NewReader, но для Version3 (блочный CRC-framing)
+// не прерывает чтение на первом повреждённом блоке, а пропускает его (см.
+// Reader.SkippedBlocks) и продолжает со следующего. Используется CLI-режимом
+// -recover. Для остальных версий формата ведёт себя как NewReader — у них
+// нет блочной структуры, пропускать нечего.
+func NewRecoveringReader(r io.ReadSeeker) (*Reader, error) {
+	return newReader(r, true)
+}
+
+func newReader(r io.ReadSeeker, recover bool) (*Reader, error) {
+	magic := make([]byte, 4)
+	n, err := io.ReadFull(r, magic)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return nil, fmt.Errorf("graphio: не удалось прочитать сигнатуру: %v", err)
+	}
+
+	if n == 4 && string(magic) == Magic {
+		return newContainerReader(r, recover)
+	}
+
+	// Не похоже на контейнер — откатываемся и пробуем легаси int16-формат.
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("graphio: не удалось перемотать файл: %v", err)
+	}
+	return newLegacyReader(r)
+}
+
+func newLegacyReader(r io.ReadSeeker) (*Reader, error) {
+	var numVertices int16
+	if err := binary.Read(r, binary.LittleEndian, &numVertices); err != nil {
+		return nil, fmt.Errorf("graphio: не удалось прочитать количество вершин (v0): %v", err)
+	}
+	if numVertices < 0 {
+		return nil, fmt.Errorf("graphio: отрицательное количество вершин (v0): %d", numVertices)
+	}
+	return &Reader{
+		version:     Version0,
+		legacy:      true,
+		numVertices: uint32(numVertices),
+		payload:     r,
+	}, nil
+}
+
+func newContainerReader(r io.ReadSeeker, recover bool) (*Reader, error) {
+	rest := make([]byte, headerSize-4)
+	if _, err := io.ReadFull(r, rest); err != nil {
+		return nil, fmt.Errorf("graphio: не удалось прочитать заголовок: %v", err)
+	}
+	version := rest[0]
+	if version != Version1 && version != Version2 && version != Version3 {
+		return nil, ErrUnsupportedVersion
+	}
+	flags := rest[1]
+	if version == Version3 && flags&(FlagGzip|FlagSnappy) != 0 {
+		return nil, ErrBlockFramingRequiresUncompressed
+	}
+	numVertices := binary.LittleEndian.Uint32(rest[2:6])
+	numEdges := binary.LittleEndian.Uint64(rest[6:14])
+
+	size, err := r.Seek(0, io.SeekEnd)
+	if err != nil {
+		return nil, fmt.Errorf("graphio: не удалось определить размер файла: %v", err)
+	}
+	if size < int64(headerSize+footerSize) {
+		return nil, fmt.Errorf("graphio: файл короче минимально возможного контейнера")
+	}
+	if _, err := r.Seek(size-footerSize, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("graphio: не удалось перейти к footer'у: %v", err)
+	}
+	footer := make([]byte, footerSize)
+	if _, err := io.ReadFull(r, footer); err != nil {
+		return nil, fmt.Errorf("graphio: не удалось прочитать footer: %v", err)
+	}
+	expectedCRC := binary.LittleEndian.Uint32(footer[0:4])
+
+	if _, err := r.Seek(headerSize, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("graphio: не удалось вернуться к полезной нагрузке: %v", err)
+	}
+	payloadLen := size - footerSize - headerSize
+	limited := io.LimitReader(r, payloadLen)
+
+	rd := &Reader{
+		version:     version,
+		flags:       flags,
+		numVertices: numVertices,
+		numEdges:    numEdges,
+		expectedCRC: expectedCRC,
+		haveFooter:  true,
+	}
+
+	if flags&FlagSnappy != 0 {
+		return nil, ErrSnappyUnsupported
+	}
+	var payload io.Reader = limited
+	switch {
+	case version == Version3:
+		rd.block = newBlockReader(limited, payloadLen, recover)
+		payload = rd.block
+	case flags&FlagGzip != 0:
+		gz, err := gzip.NewReader(limited)
+		if err != nil {
+			return nil, fmt.Errorf("graphio: не удалось открыть gzip-поток: %v", err)
+		}
+		rd.gz = gz
+		payload = gz
+	}
+	rd.payload = &crcReader{r: payload, crc: &rd.crc}
+	if version == Version2 || version == Version3 {
+		rd.byteReader = bufio.NewReader(rd.payload)
+	}
+	return rd, nil
+}
+
+// Version возвращает версию формата, в котором записан файл.
+func (rd *Reader) Version() uint8 { return rd.version }
+
+// NumVertices возвращает количество вершин графа.
+func (rd *Reader) NumVertices() uint32 { return rd.numVertices }
+
+// Next отдаёт очередное ребро, читая его по мере необходимости — в отличие
+// от ReadAll, Reader не держит уже прочитанные рёбра в памяти, так что
+// Next пригоден для обхода графов, которые не помещаются в RAM целиком
+// (см. пакет boruvka, который гоняет по Next несколько проходов). Next
+// реализует интерфейс EdgeIterator.
+func (rd *Reader) Next() (Edge, bool) {
+	if rd.done || rd.err != nil {
+		return Edge{}, false
+	}
+
+	var e Edge
+	var err error
+	switch {
+	case rd.legacy:
+		var from, to, weight int16
+		err = binary.Read(rd.payload, binary.LittleEndian, &from)
+		if err == io.EOF {
+			rd.done = true
+			return Edge{}, false
+		}
+		if err == nil {
+			err = binary.Read(rd.payload, binary.LittleEndian, &to)
+		}
+		if err == nil {
+			err = binary.Read(rd.payload, binary.LittleEndian, &weight)
+		}
+		if err != nil {
+			rd.err = fmt.Errorf("graphio: ошибка при чтении ребра %d (v0): %v", rd.read, err)
+			return Edge{}, false
+		}
+		e = Edge{From: int32(from), To: int32(to), Weight: int64(weight)}
+
+	default:
+		if rd.read >= rd.numEdges {
+			rd.done = true
+			if rd.gz != nil {
+				if err := rd.gz.Close(); err != nil {
+					rd.err = fmt.Errorf("graphio: не удалось закрыть gzip-поток: %v", err)
+					return Edge{}, false
+				}
+			}
+			skippedBlocks := rd.block != nil && rd.block.skipped > 0
+			if rd.haveFooter && rd.crc != rd.expectedCRC && !skippedBlocks {
+				rd.err = ErrChecksumMismatch
+			}
+			return Edge{}, false
+		}
+		if rd.version == Version2 || rd.version == Version3 {
+			e, err = rd.delta.decode(rd.byteReader)
+		} else { // Version1
+			var buf [6]byte
+			_, err = io.ReadFull(rd.payload, buf[:])
+			if err == nil {
+				e = Edge{
+					From:   int32(int16(binary.LittleEndian.Uint16(buf[0:2]))),
+					To:     int32(int16(binary.LittleEndian.Uint16(buf[2:4]))),
+					Weight: int64(int16(binary.LittleEndian.Uint16(buf[4:6]))),
+				}
+			}
+		}
+		if (err == io.EOF || err == io.ErrUnexpectedEOF) && rd.block != nil && rd.block.skipped > 0 {
+			// В режиме восстановления пропущенные блоки означают, что часть
+			// рёбер, заявленных в numEdges, безвозвратно потеряна — поток
+			// варинтов закончится раньше, чем rd.read догонит numEdges, и
+			// почти никогда ровно на границе записи: оборванный varint
+			// отдаёт io.ErrUnexpectedEOF, а не чистый io.EOF. Оба исхода —
+			// не ошибка, а ожидаемый результат восстановления.
+			rd.done = true
+			return Edge{}, false
+		}
+		if err != nil {
+			rd.err = fmt.Errorf("graphio: не удалось прочитать ребро %d: %v", rd.read, err)
+			return Edge{}, false
+		}
+	}
+
+	rd.read++
+	return e, true
+}
+
+// SkippedBlocks возвращает число блоков, пропущенных в режиме
+// восстановления (NewRecoveringReader) из-за несовпадения CRC32. Для
+// Reader'ов, созданных через NewReader, или для версий формата без блочной
+// структуры всегда 0.
+func (rd *Reader) SkippedBlocks() int {
+	if rd.block == nil {
+		return 0
+	}
+	return rd.block.skipped
+}
+
+// Err возвращает первую ошибку, встреченную в Next, либо nil, если поток
+// дочитан полностью и (для контейнерных версий) CRC32 сошёлся с footer'ом.
+func (rd *Reader) Err() error { return rd.err }
+
+// ReadAll — удобный помощник поверх Next/Err для случаев, когда граф заведомо
+// помещается в память.
+func (rd *Reader) ReadAll() ([]Edge, error) {
+	edges := make([]Edge, 0, rd.numEdges)
+	for {
+		e, ok := rd.Next()
+		if !ok {
+			break
+		}
+		edges = append(edges, e)
+	}
+	return edges, rd.Err()
+}
+
+// crcReader прозрачно считает CRC32 несжатых байт, проходящих через него при
+// чтении.
+type crcReader struct {
+	r   io.Reader
+	crc *uint32
+}
+
+func (c *crcReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	if n > 0 {
+		*c.crc = crc32.Update(*c.crc, crc32.IEEETable, p[:n])
+	}
+	return n, err
+}
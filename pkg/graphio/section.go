@@ -0,0 +1,128 @@
+package graphio
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// RandomAccessReader даёт доступ к рёбрам контейнера по произвольным
+// диапазонам через io.ReaderAt, так что несколько горутин могут независимо
+// сканировать непересекающиеся участки одного файла — это и используется
+// out-of-core драйвером Борувки в пакете boruvka для распараллеливания
+// прохода по рёбрам на GOMAXPROCS воркеров.
+//
+// Произвольный доступ по индексу ребра требует фиксированного размера
+// записи, поэтому поддерживается только Version1 (сырые int16-триплеты) без
+// сжатия; Version2 (varint+дельты) и gzip/snappy читаются только потоково,
+// через Reader.
+type RandomAccessReader struct {
+	ra          io.ReaderAt
+	numVertices uint32
+	numEdges    uint64
+}
+
+// NewRandomAccessReader разбирает заголовок по ra и проверяет, что файл
+// пригоден для произвольного доступа.
+func NewRandomAccessReader(ra io.ReaderAt, size int64) (*RandomAccessReader, error) {
+	header := make([]byte, headerSize)
+	if _, err := ra.ReadAt(header, 0); err != nil {
+		return nil, fmt.Errorf("graphio: не удалось прочитать заголовок: %v", err)
+	}
+	if string(header[0:4]) != Magic {
+		return nil, ErrBadMagic
+	}
+	version := header[4]
+	flags := header[5]
+	if version != Version1 {
+		return nil, fmt.Errorf("graphio: произвольный доступ поддерживается только для Version1, файл записан в Version%d", version)
+	}
+	if flags&(FlagGzip|FlagSnappy) != 0 {
+		return nil, fmt.Errorf("graphio: произвольный доступ несовместим со сжатой полезной нагрузкой")
+	}
+
+	numVertices := binary.LittleEndian.Uint32(header[6:10])
+	numEdges := binary.LittleEndian.Uint64(header[10:18])
+	wantSize := int64(headerSize) + int64(numEdges)*6 + footerSize
+	if size < wantSize {
+		return nil, fmt.Errorf("graphio: файл короче, чем заявлено в заголовке (%d ребёр): есть %d байт, нужно минимум %d", numEdges, size, wantSize)
+	}
+
+	return &RandomAccessReader{ra: ra, numVertices: numVertices, numEdges: numEdges}, nil
+}
+
+// NumVertices возвращает количество вершин графа.
+func (r *RandomAccessReader) NumVertices() uint32 { return r.numVertices }
+
+// NumEdges возвращает общее количество рёбер в файле.
+func (r *RandomAccessReader) NumEdges() uint64 { return r.numEdges }
+
+// Range возвращает независимый EdgeIterator по рёбрам с индексами
+// [start, end). Возвращённый итератор можно безопасно использовать из
+// отдельной горутины — он читает через io.NewSectionReader, не разделяя
+// состояние с другими диапазонами.
+func (r *RandomAccessReader) Range(start, end uint64) (EdgeIterator, error) {
+	if start > end || end > r.numEdges {
+		return nil, fmt.Errorf("graphio: неверный диапазон рёбер [%d, %d) при numEdges=%d", start, end, r.numEdges)
+	}
+	const recordSize = 6
+	off := int64(headerSize) + int64(start)*recordSize
+	length := int64(end-start) * recordSize
+	return &rangeIterator{sr: io.NewSectionReader(r.ra, off, length), remaining: end - start}, nil
+}
+
+// Split делит весь диапазон рёбер на n смежных частей, размеры которых
+// отличаются не больше чем на одно ребро, — удобно, чтобы раздать их
+// GOMAXPROCS воркерам, сканирующим файл параллельно. Если рёбер меньше n,
+// частей столько же, сколько рёбер (по одному ребру в каждой).
+func (r *RandomAccessReader) Split(n int) ([]EdgeIterator, error) {
+	if n < 1 {
+		n = 1
+	}
+	if uint64(n) > r.numEdges {
+		n = int(r.numEdges)
+	}
+
+	iters := make([]EdgeIterator, 0, n)
+	var start uint64
+	for i := 0; i < n; i++ {
+		size := r.numEdges / uint64(n)
+		if uint64(i) < r.numEdges%uint64(n) {
+			size++
+		}
+		it, err := r.Range(start, start+size)
+		if err != nil {
+			return nil, err
+		}
+		iters = append(iters, it)
+		start += size
+	}
+	return iters, nil
+}
+
+// rangeIterator читает фиксированные 6-байтные Version1-записи из своего
+// собственного io.SectionReader.
+type rangeIterator struct {
+	sr        *io.SectionReader
+	remaining uint64
+	err       error
+}
+
+func (it *rangeIterator) Next() (Edge, bool) {
+	if it.remaining == 0 || it.err != nil {
+		return Edge{}, false
+	}
+	var buf [6]byte
+	if _, err := io.ReadFull(it.sr, buf[:]); err != nil {
+		it.err = fmt.Errorf("graphio: не удалось прочитать ребро диапазона: %v", err)
+		return Edge{}, false
+	}
+	it.remaining--
+	return Edge{
+		From:   int32(int16(binary.LittleEndian.Uint16(buf[0:2]))),
+		To:     int32(int16(binary.LittleEndian.Uint16(buf[2:4]))),
+		Weight: int64(int16(binary.LittleEndian.Uint16(buf[4:6]))),
+	}, true
+}
+
+func (it *rangeIterator) Err() error { return it.err }
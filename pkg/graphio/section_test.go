@@ -0,0 +1,155 @@
+package graphio
+
+import (
+	"bytes"
+	"testing"
+)
+
+func buildVersion1(t *testing.T, numVertices uint32, edges []Edge) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, Options{Version: Version1}, numVertices, uint64(len(edges)))
+	if err != nil {
+		t.Fatalf("NewWriter: %v", err)
+	}
+	for _, e := range edges {
+		if err := w.WriteEdge(e); err != nil {
+			t.Fatalf("WriteEdge: %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func drain(t *testing.T, it EdgeIterator) []Edge {
+	t.Helper()
+	var got []Edge
+	for {
+		e, ok := it.Next()
+		if !ok {
+			break
+		}
+		got = append(got, e)
+	}
+	if err := it.Err(); err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	return got
+}
+
+func TestNewRandomAccessReaderRejectsVersion2(t *testing.T) {
+	data := func() []byte {
+		var buf bytes.Buffer
+		w, err := NewWriter(&buf, Options{Version: Version2}, 3, 1)
+		if err != nil {
+			t.Fatalf("NewWriter: %v", err)
+		}
+		if err := w.WriteEdge(Edge{From: 0, To: 1, Weight: 1}); err != nil {
+			t.Fatalf("WriteEdge: %v", err)
+		}
+		if err := w.Close(); err != nil {
+			t.Fatalf("Close: %v", err)
+		}
+		return buf.Bytes()
+	}()
+
+	ra := bytes.NewReader(data)
+	if _, err := NewRandomAccessReader(ra, int64(len(data))); err == nil {
+		t.Fatal("NewRandomAccessReader() = nil на Version2, хотим ошибку — произвольный доступ требует Version1")
+	}
+}
+
+func TestNewRandomAccessReaderRejectsGzip(t *testing.T) {
+	data := func() []byte {
+		var buf bytes.Buffer
+		w, err := NewWriter(&buf, Options{Version: Version1, Gzip: true}, 3, 1)
+		if err != nil {
+			t.Fatalf("NewWriter: %v", err)
+		}
+		if err := w.WriteEdge(Edge{From: 0, To: 1, Weight: 1}); err != nil {
+			t.Fatalf("WriteEdge: %v", err)
+		}
+		if err := w.Close(); err != nil {
+			t.Fatalf("Close: %v", err)
+		}
+		return buf.Bytes()
+	}()
+
+	ra := bytes.NewReader(data)
+	if _, err := NewRandomAccessReader(ra, int64(len(data))); err == nil {
+		t.Fatal("NewRandomAccessReader() = nil на сжатом Version1, хотим ошибку")
+	}
+}
+
+func TestRandomAccessReaderRange(t *testing.T) {
+	edges := []Edge{
+		{From: 0, To: 1, Weight: 1},
+		{From: 1, To: 2, Weight: 2},
+		{From: 2, To: 3, Weight: 3},
+		{From: 3, To: 4, Weight: 4},
+	}
+	data := buildVersion1(t, 5, edges)
+	ra, err := NewRandomAccessReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("NewRandomAccessReader: %v", err)
+	}
+	if ra.NumVertices() != 5 {
+		t.Fatalf("NumVertices() = %d, хотим 5", ra.NumVertices())
+	}
+	if ra.NumEdges() != uint64(len(edges)) {
+		t.Fatalf("NumEdges() = %d, хотим %d", ra.NumEdges(), len(edges))
+	}
+
+	it, err := ra.Range(1, 3)
+	if err != nil {
+		t.Fatalf("Range: %v", err)
+	}
+	got := drain(t, it)
+	want := edges[1:3]
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Fatalf("Range(1, 3) = %+v, хотим %+v", got, want)
+	}
+
+	if _, err := ra.Range(2, 1); err == nil {
+		t.Fatal("Range(2, 1) = nil, хотим ошибку на start > end")
+	}
+	if _, err := ra.Range(0, uint64(len(edges)+1)); err == nil {
+		t.Fatal("Range() за пределы numEdges = nil, хотим ошибку")
+	}
+}
+
+// TestRandomAccessReaderSplit проверяет, что Split делит рёбра на
+// непересекающиеся смежные диапазоны, покрывающие весь файл без потерь и
+// дублей — это и есть инвариант, на котором держится MSTParallel.
+func TestRandomAccessReaderSplit(t *testing.T) {
+	edges := make([]Edge, 0, 997)
+	for i := 0; i < 997; i++ { // простое число рёбер — проверяет неровный остаток
+		edges = append(edges, Edge{From: int32(i), To: int32(i + 1), Weight: int64(i)})
+	}
+	data := buildVersion1(t, 1000, edges)
+	ra, err := NewRandomAccessReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("NewRandomAccessReader: %v", err)
+	}
+
+	for _, workers := range []int{1, 3, 4, 16, 2000} {
+		iters, err := ra.Split(workers)
+		if err != nil {
+			t.Fatalf("Split(%d): %v", workers, err)
+		}
+		var got []Edge
+		for _, it := range iters {
+			got = append(got, drain(t, it)...)
+		}
+		if len(got) != len(edges) {
+			t.Fatalf("Split(%d): всего получено %d рёбер, хотим %d", workers, len(got), len(edges))
+		}
+		for i := range edges {
+			if got[i] != edges[i] {
+				t.Fatalf("Split(%d): ребро %d = %+v, хотим %+v", workers, i, got[i], edges[i])
+			}
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package graphio
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// textWriter пишет рёбра простыми строками "from,to,weight", без заголовка —
+// формат, который проще всего скормить внешним инструментам.
+type textWriter struct {
+	w    *bufio.Writer
+	file *os.File
+}
+
+func newTextWriter(file *os.File) *textWriter {
+	return &textWriter{w: bufio.NewWriter(file), file: file}
+}
+
+func (tw *textWriter) WriteEdge(e Edge) error {
+	_, err := fmt.Fprintf(tw.w, "%d,%d,%d\n", e.From, e.To, e.Weight)
+	if err != nil {
+		return fmt.Errorf("graphio: не удалось записать строку text-формата: %v", err)
+	}
+	return nil
+}
+
+func (tw *textWriter) Close() error {
+	if err := tw.w.Flush(); err != nil {
+		return fmt.Errorf("graphio: не удалось сбросить буфер: %v", err)
+	}
+	return tw.file.Close()
+}
+
+// textReader читает строки "from,to,weight" (пустые строки и строки,
+// начинающиеся с '#', пропускаются). Поскольку у формата нет заголовка с
+// числом вершин, NumVertices — это максимальный увиденный индекс вершины
+// плюс один и становится точным только после полного прохода.
+type textReader struct {
+	file      *os.File
+	sc        *bufio.Scanner
+	maxVertex int32
+	err       error
+	done      bool
+}
+
+func newTextReader(file *os.File) *textReader {
+	return &textReader{file: file, sc: bufio.NewScanner(file)}
+}
+
+func (tr *textReader) Next() (Edge, bool) {
+	if tr.done || tr.err != nil {
+		return Edge{}, false
+	}
+	for tr.sc.Scan() {
+		line := strings.TrimSpace(tr.sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		parts := strings.Split(line, ",")
+		if len(parts) != 3 {
+			tr.err = fmt.Errorf("graphio: неверная строка text-формата: %q", line)
+			return Edge{}, false
+		}
+		from, err1 := strconv.ParseInt(strings.TrimSpace(parts[0]), 10, 32)
+		to, err2 := strconv.ParseInt(strings.TrimSpace(parts[1]), 10, 32)
+		weight, err3 := strconv.ParseInt(strings.TrimSpace(parts[2]), 10, 64)
+		if err1 != nil || err2 != nil || err3 != nil {
+			tr.err = fmt.Errorf("graphio: не удалось разобрать строку text-формата: %q", line)
+			return Edge{}, false
+		}
+		e := Edge{From: int32(from), To: int32(to), Weight: weight}
+		if e.From > tr.maxVertex {
+			tr.maxVertex = e.From
+		}
+		if e.To > tr.maxVertex {
+			tr.maxVertex = e.To
+		}
+		return e, true
+	}
+	tr.done = true
+	tr.err = tr.sc.Err()
+	return Edge{}, false
+}
+
+func (tr *textReader) Err() error { return tr.err }
+
+func (tr *textReader) NumVertices() uint32 { return uint32(tr.maxVertex + 1) }
+
+func (tr *textReader) Close() error { return tr.file.Close() }
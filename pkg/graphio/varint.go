@@ -0,0 +1,89 @@
+package graphio
+
+import (
+	"encoding/binary"
+	"io"
+)
+
+// zigzagEncode отображает знаковое число на беззнаковое так, что маленькие
+// по модулю значения (как положительные, так и отрицательные) получают
+// маленький код — это и делает дельты компактными после varint-кодирования.
+func zigzagEncode(v int64) uint64 {
+	return uint64((v << 1) ^ (v >> 63))
+}
+
+// zigzagDecode — обратное преобразование к zigzagEncode.
+func zigzagDecode(u uint64) int64 {
+	return int64(u>>1) ^ -int64(u&1)
+}
+
+// writeVarint пишет zigzag-дельту как varint в w.
+func writeVarint(w io.Writer, delta int64) error {
+	var buf [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(buf[:], zigzagEncode(delta))
+	_, err := w.Write(buf[:n])
+	return err
+}
+
+// readVarint читает zigzag-varint из br и возвращает исходную (знаковую)
+// дельту.
+func readVarint(br io.ByteReader) (int64, error) {
+	u, err := binary.ReadUvarint(br)
+	if err != nil {
+		return 0, err
+	}
+	return zigzagDecode(u), nil
+}
+
+// edgeDeltaState хранит "last"-значения, относительно которых считаются
+// дельты при varint-кодировании рёбер (Version2): from и weight — сквозные
+// бегущие суммы, а to сбрасывается в 0 при каждой смене from (рёбра
+// предполагаются отсортированными по (from, to), так что to внутри одной
+// группы from обычно монотонно растёт).
+type edgeDeltaState struct {
+	from, toInGroup int32
+	weight          int64
+}
+
+func (s *edgeDeltaState) encode(w io.Writer, e Edge) error {
+	if err := writeVarint(w, int64(e.From)-int64(s.from)); err != nil {
+		return err
+	}
+	if e.From != s.from {
+		s.toInGroup = 0
+	}
+	if err := writeVarint(w, int64(e.To)-int64(s.toInGroup)); err != nil {
+		return err
+	}
+	if err := writeVarint(w, e.Weight-s.weight); err != nil {
+		return err
+	}
+	s.from, s.toInGroup, s.weight = e.From, e.To, e.Weight
+	return nil
+}
+
+func (s *edgeDeltaState) decode(br io.ByteReader) (Edge, error) {
+	dFrom, err := readVarint(br)
+	if err != nil {
+		return Edge{}, err
+	}
+	from := s.from + int32(dFrom)
+	if from != s.from {
+		s.toInGroup = 0
+	}
+
+	dTo, err := readVarint(br)
+	if err != nil {
+		return Edge{}, err
+	}
+	to := s.toInGroup + int32(dTo)
+
+	dWeight, err := readVarint(br)
+	if err != nil {
+		return Edge{}, err
+	}
+	weight := s.weight + dWeight
+
+	s.from, s.toInGroup, s.weight = from, to, weight
+	return Edge{From: from, To: to, Weight: weight}, nil
+}
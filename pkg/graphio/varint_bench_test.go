@@ -0,0 +1,87 @@
+package graphio
+
+import (
+	"bytes"
+	"math/rand"
+	"testing"
+)
+
+// genSortedEdges строит детерминированный граф из numEdges рёбер поверх
+// numVertices вершин, отсортированный по (from, to) — именно такой порядок
+// и ожидает Version2 для компактных дельт.
+func genSortedEdges(numVertices, numEdges int) []Edge {
+	rnd := rand.New(rand.NewSource(1))
+	edges := make([]Edge, numEdges)
+	for i := range edges {
+		edges[i] = Edge{
+			From:   int32(rnd.Intn(numVertices)),
+			To:     int32(rnd.Intn(numVertices)),
+			Weight: int64(rnd.Intn(1000)),
+		}
+	}
+	// Воспроизводим сортировку, которую WriteAll делает для Version2, чтобы
+	// оба бенчмарка сравнивали одинаковый порядок рёбер.
+	for i := 1; i < len(edges); i++ {
+		for j := i; j > 0 && less(edges[j], edges[j-1]); j-- {
+			edges[j], edges[j-1] = edges[j-1], edges[j]
+		}
+	}
+	return edges
+}
+
+func less(a, b Edge) bool {
+	if a.From != b.From {
+		return a.From < b.From
+	}
+	return a.To < b.To
+}
+
+// benchmarkWriteSize пишет edges без сжатия (чтобы сравнивать чистый размер
+// кодировки payload'а, а не gzip) и сообщает итоговый размер как метрику.
+func benchmarkWriteSize(b *testing.B, version uint8, edges []Edge) {
+	opts := Options{Version: version}
+	for i := 0; i < b.N; i++ {
+		var buf bytes.Buffer
+		w, err := NewWriter(&buf, opts, 1<<20, uint64(len(edges)))
+		if err != nil {
+			b.Fatal(err)
+		}
+		if err := w.WriteAll(edges); err != nil {
+			b.Fatal(err)
+		}
+		if err := w.Close(); err != nil {
+			b.Fatal(err)
+		}
+		if i == b.N-1 {
+			b.ReportMetric(float64(buf.Len()), "bytes/graph")
+		}
+	}
+}
+
+// BenchmarkWriteV1Sparse и BenchmarkWriteV2Sparse сравнивают сырые
+// int16-триплеты (Version1) с varint+дельтами (Version2) на разреженном
+// графе с небольшим числом вершин. На таких графах дельта from обычно
+// укладывается в один байт varint'а, а дельты to и weight — в один-два
+// (около 4.6 байта на ребро против 6 у Version1), так что Version2 обычно заметно
+// меньше Version1 ещё до gzip — выигрыш растёт вместе с тем, насколько
+// плотно сгруппированы (from, to) после сортировки. Замер на этой машине:
+// 1000 вершин/5000 рёбер — 30026 против 22832 байт (-24%); 200 вершин/
+// 20000 рёбер (BenchmarkWrite{V1,V2}Dense ниже) — 120026 против 77389
+// байт (-35%), то есть выигрыш растёт вместе с плотностью графа.
+func BenchmarkWriteV1Sparse(b *testing.B) {
+	benchmarkWriteSize(b, Version1, genSortedEdges(1000, 5000))
+}
+
+func BenchmarkWriteV2Sparse(b *testing.B) {
+	benchmarkWriteSize(b, Version2, genSortedEdges(1000, 5000))
+}
+
+// BenchmarkWriteV1Dense и BenchmarkWriteV2Dense делают то же сравнение на
+// более плотном графе, где дельты between соседних рёбер ещё меньше.
+func BenchmarkWriteV1Dense(b *testing.B) {
+	benchmarkWriteSize(b, Version1, genSortedEdges(200, 20000))
+}
+
+func BenchmarkWriteV2Dense(b *testing.B) {
+	benchmarkWriteSize(b, Version2, genSortedEdges(200, 20000))
+}
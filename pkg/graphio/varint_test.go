@@ -0,0 +1,45 @@
+package graphio
+
+import (
+	"bufio"
+	"bytes"
+	"testing"
+)
+
+func TestZigzagRoundTrip(t *testing.T) {
+	for _, v := range []int64{0, 1, -1, 63, -64, 1 << 20, -(1 << 20), 1<<62 - 1, -(1 << 62)} {
+		if got := zigzagDecode(zigzagEncode(v)); got != v {
+			t.Fatalf("zigzagDecode(zigzagEncode(%d)) = %d", v, got)
+		}
+	}
+}
+
+func TestEdgeDeltaStateRoundTrip(t *testing.T) {
+	edges := []Edge{
+		{From: 0, To: 5, Weight: 10},
+		{From: 0, To: 7, Weight: -3}, // тот же from: to считается внутри группы
+		{From: 2, To: 1, Weight: 100},
+		{From: 2, To: 1, Weight: 100}, // нулевые дельты по всем полям
+		{From: 1, To: 9, Weight: -50}, // from уменьшается — дельта отрицательная
+	}
+
+	var buf bytes.Buffer
+	var enc edgeDeltaState
+	for _, e := range edges {
+		if err := enc.encode(&buf, e); err != nil {
+			t.Fatalf("encode: %v", err)
+		}
+	}
+
+	var dec edgeDeltaState
+	br := bufio.NewReader(&buf)
+	for i, want := range edges {
+		got, err := dec.decode(br)
+		if err != nil {
+			t.Fatalf("decode ребра %d: %v", i, err)
+		}
+		if got != want {
+			t.Fatalf("ребро %d: получили %+v, хотим %+v", i, got, want)
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package graphio
+
+import (
+	"fmt"
+	"os"
+)
+
+// Verify проверяет целостность файла графа, не складывая рёбра никуда —
+// только сам проход через Reader.Next. Для Version3 это обнаруживает первый
+// повреждённый блок (см. blockReader); для более старых версий — несовпадение
+// CRC32 всего footer'а после полного прохода. См. CLI-подкоманду verify и
+// NewRecoveringReader, если нужно не упасть на первом повреждении, а узнать
+// обо всех сразу.
+func Verify(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("graphio: не удалось открыть %s: %v", path, err)
+	}
+	defer file.Close()
+
+	r, err := NewReader(file)
+	if err != nil {
+		return err
+	}
+	for {
+		if _, ok := r.Next(); !ok {
+			break
+		}
+	}
+	return r.Err()
+}
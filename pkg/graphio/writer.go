@@ -0,0 +1,178 @@
+package graphio
+
+import (
+	"compress/gzip"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"sort"
+)
+
+// Writer пишет граф в контейнерном формате: заголовок, затем полезная
+// нагрузка (Version1 — сырые int16-триплеты, Version2 — varint+дельты,
+// Version3 — как Version2, но с crc32 после каждого blockSize-блока;
+// опционально сжатая для Version1/Version2), затем footer с CRC32 несжатых
+// данных.
+type Writer struct {
+	dst     io.Writer
+	version uint8
+	crc     uint32
+	gz      *gzip.Writer
+	block   *blockWriter
+	payload io.Writer // получает несжатые байты, сам считает crc и при нужде сжимает/режет на блоки
+	delta   edgeDeltaState
+	closed  bool
+}
+
+// NewWriter создаёт Writer, немедленно записывая заголовок. numVertices и
+// numEdges должны быть известны заранее, так как пишутся в заголовок перед
+// полезной нагрузкой.
+func NewWriter(w io.Writer, opts Options, numVertices uint32, numEdges uint64) (*Writer, error) {
+	if opts.Snappy && opts.Version != Version3 {
+		return nil, ErrSnappyUnsupported
+	}
+
+	version := opts.Version
+	if version == 0 {
+		version = Version2
+	}
+	if version != Version1 && version != Version2 && version != Version3 {
+		return nil, ErrUnsupportedVersion
+	}
+	if version == Version3 && (opts.Gzip || opts.Snappy) {
+		return nil, ErrBlockFramingRequiresUncompressed
+	}
+
+	var flags uint8
+	if opts.Gzip {
+		flags |= FlagGzip
+	}
+	if opts.Directed {
+		flags |= FlagDirected
+	}
+
+	header := make([]byte, headerSize)
+	copy(header[0:4], Magic)
+	header[4] = version
+	header[5] = flags
+	binary.LittleEndian.PutUint32(header[6:10], numVertices)
+	binary.LittleEndian.PutUint64(header[10:18], numEdges)
+	if _, err := w.Write(header); err != nil {
+		return nil, fmt.Errorf("graphio: не удалось записать заголовок: %v", err)
+	}
+
+	wr := &Writer{dst: w, version: version}
+	switch {
+	case version == Version3:
+		wr.block = newBlockWriter(w)
+		wr.payload = &crcWriter{w: wr.block, crc: &wr.crc}
+	case opts.Gzip:
+		wr.gz = gzip.NewWriter(w)
+		wr.payload = &crcWriter{w: wr.gz, crc: &wr.crc}
+	default:
+		wr.payload = &crcWriter{w: w, crc: &wr.crc}
+	}
+	return wr, nil
+}
+
+// WriteEdge пишет одно ребро. Для Version1 поля должны помещаться в int16
+// (иначе возвращается ErrOutOfRange); для Version2/Version3 вызывающий код
+// сам отвечает за то, чтобы рёбра подавались отсортированными по (from, to)
+// — иначе дельты просто окажутся длиннее, но декодирование останется
+// корректным.
+func (wr *Writer) WriteEdge(e Edge) error {
+	switch wr.version {
+	case Version1:
+		if e.From < minInt16 || e.From > maxInt16 || e.To < minInt16 || e.To > maxInt16 ||
+			e.Weight < minInt16 || e.Weight > maxInt16 {
+			return ErrOutOfRange
+		}
+		var buf [6]byte
+		binary.LittleEndian.PutUint16(buf[0:2], uint16(int16(e.From)))
+		binary.LittleEndian.PutUint16(buf[2:4], uint16(int16(e.To)))
+		binary.LittleEndian.PutUint16(buf[4:6], uint16(int16(e.Weight)))
+		if _, err := wr.payload.Write(buf[:]); err != nil {
+			return fmt.Errorf("graphio: не удалось записать ребро: %v", err)
+		}
+		return nil
+	case Version2, Version3:
+		if err := wr.delta.encode(wr.payload, e); err != nil {
+			return fmt.Errorf("graphio: не удалось записать ребро: %v", err)
+		}
+		return nil
+	default:
+		return ErrUnsupportedVersion
+	}
+}
+
+const (
+	minInt16 = -1 << 15
+	maxInt16 = 1<<15 - 1
+)
+
+// WriteAll пишет все рёбра разом. Для Version2/Version3 рёбра
+// предварительно сортируются по (From, To) — это и есть расчёт на малые
+// дельты, который делает varint-кодирование компактным; исходный срез не
+// меняется.
+func (wr *Writer) WriteAll(edges []Edge) error {
+	if wr.version == Version2 || wr.version == Version3 {
+		sorted := make([]Edge, len(edges))
+		copy(sorted, edges)
+		sort.Slice(sorted, func(i, j int) bool {
+			if sorted[i].From != sorted[j].From {
+				return sorted[i].From < sorted[j].From
+			}
+			return sorted[i].To < sorted[j].To
+		})
+		edges = sorted
+	}
+	for _, e := range edges {
+		if err := wr.WriteEdge(e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close закрывает компрессор (если он использовался) и дописывает footer:
+// CRC32 несжатой полезной нагрузки и смещение заголовка (всегда 0, заголовок
+// мы пишем один раз в начале файла).
+func (wr *Writer) Close() error {
+	if wr.closed {
+		return nil
+	}
+	wr.closed = true
+
+	if wr.gz != nil {
+		if err := wr.gz.Close(); err != nil {
+			return fmt.Errorf("graphio: не удалось закрыть gzip-поток: %v", err)
+		}
+	}
+	if wr.block != nil {
+		if err := wr.block.Close(); err != nil {
+			return err
+		}
+	}
+
+	footer := make([]byte, footerSize)
+	binary.LittleEndian.PutUint32(footer[0:4], wr.crc)
+	binary.LittleEndian.PutUint32(footer[4:8], 0)
+	if _, err := wr.dst.Write(footer); err != nil {
+		return fmt.Errorf("graphio: не удалось записать footer: %v", err)
+	}
+	return nil
+}
+
+// crcWriter прозрачно считает CRC32 несжатых байт, которые через него
+// проходят, прежде чем передать их дальше (в компрессор либо напрямую в
+// файл).
+type crcWriter struct {
+	w   io.Writer
+	crc *uint32
+}
+
+func (c *crcWriter) Write(p []byte) (int, error) {
+	*c.crc = crc32.Update(*c.crc, crc32.IEEETable, p)
+	return c.w.Write(p)
+}